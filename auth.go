@@ -0,0 +1,203 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Authenticator adds credentials to an outgoing request. It runs on every attempt of a request, after the middleware
+// chain, so credentials are never part of the headers seen by middleware and can be rotated between retries.
+type Authenticator interface {
+	Authenticate(request *http.Request) error
+}
+
+// Invalidator is implemented by Authenticators whose credentials can be revoked before they expire, such as OAuth2
+// access tokens. When a request is answered with 401 Unauthorized, Invalidate is called with the rejected request and
+// the request is authenticated again and retried once, provided its body can be replayed.
+type Invalidator interface {
+	Invalidate(request *http.Request)
+}
+
+// Challenger is implemented by Authenticators that answer the challenge of a 401 Unauthorized response, such as
+// Digest authentication. Challenge is called with the rejected request and the response, and reports whether the
+// request should be authenticated again and retried once, provided its body can be replayed.
+type Challenger interface {
+	Challenge(request *http.Request, response *http.Response) bool
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface.
+type AuthenticatorFunc func(request *http.Request) error
+
+// Authenticate calls f with the request.
+func (f AuthenticatorFunc) Authenticate(request *http.Request) error {
+	return f(request)
+}
+
+// TokenFunc returns the token used for a request bound to ctx.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// ErrEmptyToken is returned when a token function returns an empty token.
+var ErrEmptyToken = errors.New("empty token")
+
+// BasicAuth returns an Authenticator that sends the username and password with HTTP basic authentication.
+func BasicAuth(username, password string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		request.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// BearerToken returns an Authenticator that sends a static token in the Authorization header.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// BearerTokenFunc returns an Authenticator that sends the token returned by tokenFunc in the Authorization header.
+// The function is called for every attempt, which allows tokens to be rotated without rebuilding the client.
+func BearerTokenFunc(tokenFunc TokenFunc) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		token, err := tokenFunc(request.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get bearer token: %w", err)
+		}
+		if token == "" {
+			return fmt.Errorf("failed to get bearer token: %w", ErrEmptyToken)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKeyHeader returns an Authenticator that sends the API key in the header with the given name.
+func APIKeyHeader(name, key string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		request.Header.Set(name, key)
+		return nil
+	})
+}
+
+// APIKeyQuery returns an Authenticator that sends the API key in the query parameter with the given name.
+func APIKeyQuery(name, key string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		query := request.URL.Query()
+		query.Set(name, key)
+		request.URL.RawQuery = query.Encode()
+		return nil
+	})
+}
+
+// NoAuth returns an Authenticator that adds no credentials. It disables the client's authenticator for a request
+// when given to WithAuthenticator or RequestBuilder.Auth.
+func NoAuth() Authenticator {
+	return AuthenticatorFunc(func(*http.Request) error {
+		return nil
+	})
+}
+
+// ChainAuthenticators returns an Authenticator that runs the given authenticators in order, stopping at the first
+// error. A signer placed after a token authenticator therefore covers the Authorization header it sets. When a request
+// is answered with 401 Unauthorized, every authenticator handles the response and the request is retried once if any
+// of them asks for it.
+func ChainAuthenticators(first Authenticator, rest ...Authenticator) Authenticator {
+	return authenticatorChain(append([]Authenticator{first}, rest...))
+}
+
+// authenticatorChain is an Authenticator running several authenticators in order.
+type authenticatorChain []Authenticator
+
+// Authenticate authenticates the request with each authenticator of the chain.
+func (c authenticatorChain) Authenticate(request *http.Request) error {
+	for _, authenticator := range c {
+		if err := authenticator.Authenticate(request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Challenge lets each authenticator of the chain handle the 401 Unauthorized response and reports whether any of
+// them asks for the request to be retried.
+func (c authenticatorChain) Challenge(request *http.Request, response *http.Response) bool {
+	retry := false
+	for _, authenticator := range c {
+		if retryAuthentication(authenticator, request, response) {
+			retry = true
+		}
+	}
+	return retry
+}
+
+// SetAuthenticator sets the Authenticator applied to every request sent by the client.
+// It can be overridden per request with WithAuthenticator or RequestBuilder.Auth.
+func (cb *ClientBuilder) SetAuthenticator(authenticator Authenticator) *ClientBuilder {
+	cb.authenticator = authenticator
+	return cb
+}
+
+// Auth overrides the client's Authenticator for the request.
+func (rb *RequestBuilder) Auth(authenticator Authenticator) *RequestBuilder {
+	rb.options = append(rb.options, func(ctx context.Context) context.Context {
+		return WithAuthenticator(ctx, authenticator)
+	})
+	return rb
+}
+
+// getAuthenticator returns the Authenticator for the request bound to ctx, preferring a per-request override.
+func (c *Client) getAuthenticator(ctx context.Context) Authenticator {
+	if authenticator, ok := authenticatorFromContext(ctx); ok {
+		return authenticator
+	}
+	return c.authenticator
+}
+
+// attempt authenticates a copy of the request and sends it through the underlying http.Client.
+// It is called once per attempt so that every retry carries fresh credentials.
+func (c *Client) attempt(request *http.Request) (*http.Response, error) {
+	authenticator := c.getAuthenticator(request.Context())
+	if authenticator == nil {
+		return c.client.Do(request)
+	}
+
+	authenticated, response, err := c.authenticateAndSend(authenticator, request)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+	next, ok := rewind(request)
+	if !ok || !retryAuthentication(authenticator, authenticated, response) {
+		return response, nil
+	}
+
+	drain(response.Body)
+	_, response, err = c.authenticateAndSend(authenticator, next)
+	return response, err
+}
+
+// retryAuthentication lets the authenticator handle a 401 Unauthorized response and reports whether the rejected
+// request should be retried.
+func retryAuthentication(authenticator Authenticator, request *http.Request, response *http.Response) bool {
+	switch handler := authenticator.(type) {
+	case Challenger:
+		return handler.Challenge(request, response)
+	case Invalidator:
+		handler.Invalidate(request)
+		return true
+	default:
+		return false
+	}
+}
+
+// authenticateAndSend sends an authenticated copy of the request, which is returned alongside the response.
+// The transport of the client is made available to the authenticator through the request context.
+func (c *Client) authenticateAndSend(authenticator Authenticator, request *http.Request) (*http.Request, *http.Response, error) {
+	authenticated := request.Clone(withTransport(request.Context(), c.client.Transport))
+	if err := authenticator.Authenticate(authenticated); err != nil {
+		return nil, nil, err
+	}
+	response, err := c.client.Do(authenticated)
+	return authenticated, response, err
+}
@@ -0,0 +1,143 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// newAuthEchoServer starts a server that echoes the Authorization header, the X-Api-Key header, and the api_key query
+// parameter of each request.
+func newAuthEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-Api-Key") + "|" + r.URL.Query().Get("api_key")))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestAuthenticators verifies the credentials sent by each built-in Authenticator.
+func TestAuthenticators(t *testing.T) {
+	server := newAuthEchoServer(t)
+
+	tests := []struct {
+		name          string
+		authenticator Authenticator
+		expected      string
+	}{
+		{"basic", BasicAuth("user", "pass"), "Basic dXNlcjpwYXNz||"},
+		{"bearer", BearerToken("static"), "Bearer static||"},
+		{"bearerFunc", BearerTokenFunc(func(ctx context.Context) (string, error) { return "dynamic", nil }), "Bearer dynamic||"},
+		{"apiKeyHeader", APIKeyHeader("X-Api-Key", "secret"), "|secret|"},
+		{"apiKeyQuery", APIKeyQuery("api_key", "secret"), "||secret"},
+		{"none", NoAuth(), "||"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientBuilder().SetAuthenticator(tt.authenticator).Build()
+			res, err := client.Get(server.URL+"?page=2", nil)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if res.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, res.String())
+			}
+		})
+	}
+}
+
+// TestAuthenticator_Override verifies that the client's Authenticator can be overridden per request.
+func TestAuthenticator_Override(t *testing.T) {
+	server := newAuthEchoServer(t)
+	client := NewClientBuilder().SetAuthenticator(BearerToken("default")).Build()
+
+	t.Run("context", func(t *testing.T) {
+		ctx := WithAuthenticator(context.Background(), BasicAuth("user", "pass"))
+		res, _ := client.GetContext(ctx, server.URL, nil)
+		if res.String() != "Basic dXNlcjpwYXNz||" {
+			t.Errorf("expected basic credentials, got %q", res.String())
+		}
+	})
+
+	t.Run("requestBuilder", func(t *testing.T) {
+		res, _ := client.R().Auth(APIKeyHeader("X-Api-Key", "override")).Get(server.URL)
+		if res.String() != "|override|" {
+			t.Errorf("expected API key, got %q", res.String())
+		}
+	})
+
+	t.Run("noAuth", func(t *testing.T) {
+		res, _ := client.R().Auth(NoAuth()).Get(server.URL)
+		if res.String() != "||" {
+			t.Errorf("expected no credentials, got %q", res.String())
+		}
+	})
+}
+
+// TestAuthenticator_EachAttempt verifies that credentials are refreshed on every retry and hidden from middleware.
+func TestAuthenticator_EachAttempt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var seen string
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: 1, Jitter: NoJitter}).
+		SetAuthenticator(BearerTokenFunc(func(ctx context.Context) (string, error) {
+			return "token-" + strconv.Itoa(int(calls.Add(1))), nil
+		})).
+		Use(func(next Handler) Handler {
+			return func(request *http.Request) (*Response, error) {
+				seen = request.Header.Get("Authorization")
+				return next(request)
+			}
+		}).
+		Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", res.Attempts())
+	}
+	if seen != "" {
+		t.Errorf("expected middleware not to see credentials, got %q", seen)
+	}
+}
+
+// TestBearerTokenFunc_Error verifies that failing to get a token aborts the request.
+func TestBearerTokenFunc_Error(t *testing.T) {
+	server := newAuthEchoServer(t)
+	failure := errors.New("vault unavailable")
+
+	tests := []struct {
+		name     string
+		token    TokenFunc
+		expected error
+	}{
+		{"error", func(ctx context.Context) (string, error) { return "", failure }, failure},
+		{"empty", func(ctx context.Context) (string, error) { return "", nil }, ErrEmptyToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientBuilder().SetAuthenticator(BearerTokenFunc(tt.token)).Build()
+			if _, err := client.Get(server.URL, nil); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
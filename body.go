@@ -0,0 +1,77 @@
+package webs
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ContentTypeForm represents the MIME type for URL-encoded form data: "application/x-www-form-urlencoded".
+const ContentTypeForm = "application/x-www-form-urlencoded"
+
+// encodeBody converts a request body into a reader. Raw bodies ([]byte, string, and io.Reader) are sent unchanged,
+// readers being streamed without buffering, and url.Values are form-encoded with the form Content-Type, keeping an
+// explicit one only when it is form-compatible. A *Multipart body is streamed with its boundary Content-Type. Any other value is marshalled with the codec
+// matching the Content-Type header.
+func (c *Client) encodeBody(headers http.Header, body interface{}) (io.Reader, error) {
+	switch value := body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return bytes.NewReader(value), nil
+	case string:
+		return strings.NewReader(value), nil
+	case *Multipart:
+		reader, contentType := value.reader()
+		headers.Set("Content-Type", contentType)
+		return reader, nil
+	case url.Values:
+		headers.Set("Content-Type", formContentType(headers.Values("Content-Type")))
+		return strings.NewReader(value.Encode()), nil
+	case io.Reader:
+		return value, nil
+	default:
+		data, err := c.getCodecs().marshal(headers.Get("Content-Type"), value)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(data), nil
+	}
+}
+
+// formContentType returns the first form-compatible Content-Type among values, such as one with a charset parameter,
+// or ContentTypeForm when there is none.
+func formContentType(values []string) string {
+	for _, value := range values {
+		if mediaType, _, err := mime.ParseMediaType(value); err == nil && mediaType == ContentTypeForm {
+			return value
+		}
+	}
+	return ContentTypeForm
+}
+
+// hashRequestBody writes the body of the request to h through GetBody, leaving the body itself unread.
+// Requests with a body that cannot be replayed fail with ErrUnsignableBody.
+func hashRequestBody(request *http.Request, h hash.Hash) error {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil
+	}
+	if request.GetBody == nil {
+		return ErrUnsignableBody
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsignableBody, err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(body)
+
+	_, err = io.Copy(h, body)
+	return err
+}
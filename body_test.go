@@ -0,0 +1,140 @@
+package webs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// echoServer returns a test server that echoes the request body and reports its Content-Type and Content-Length.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Content-Length", r.Header.Get("Content-Length"))
+		w.Header().Set("X-Transfer-Encoding", strings.Join(r.TransferEncoding, ","))
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClient_RawBodies verifies that []byte, string, and io.Reader bodies are sent unchanged.
+func TestClient_RawBodies(t *testing.T) {
+	server := echoServer(t)
+	client := NewClientBuilder().Build()
+
+	tests := []struct {
+		name string
+		body interface{}
+	}{
+		{"bytes", []byte(`{"raw":true}`)},
+		{"string", `{"raw":true}`},
+		{"reader", io.NopCloser(strings.NewReader(`{"raw":true}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Post(server.URL, nil, tt.body)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if res.String() != `{"raw":true}` {
+				t.Errorf("expected body to be sent unchanged, got %s", res.String())
+			}
+		})
+	}
+}
+
+// TestClient_ReaderBodyIsStreamed verifies that an io.Reader of unknown length is streamed with chunked encoding.
+func TestClient_ReaderBodyIsStreamed(t *testing.T) {
+	server := echoServer(t)
+	client := NewClientBuilder().Build()
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = writer.Write([]byte("streamed"))
+		_ = writer.Close()
+	}()
+
+	res, err := client.Put(server.URL, nil, reader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "streamed" {
+		t.Errorf("expected body streamed, got %s", res.String())
+	}
+	if res.Headers().Get("X-Transfer-Encoding") != "chunked" {
+		t.Errorf("expected chunked transfer encoding, got %q", res.Headers().Get("X-Transfer-Encoding"))
+	}
+}
+
+// TestClient_FormBody verifies that url.Values are form-encoded and the Content-Type is set automatically.
+func TestClient_FormBody(t *testing.T) {
+	server := echoServer(t)
+	client := NewClientBuilder().Build()
+
+	form := url.Values{}
+	form.Set("name", "John Doe")
+	form.Add("tag", "a&b")
+
+	res, err := client.Post(server.URL, nil, form)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "name=John+Doe&tag=a%26b" {
+		t.Errorf("expected form-encoded body, got %s", res.String())
+	}
+	if res.Headers().Get("X-Content-Type") != ContentTypeForm {
+		t.Errorf("expected Content-Type %s, got %s", ContentTypeForm, res.Headers().Get("X-Content-Type"))
+	}
+}
+
+// TestClient_FormBodyKeepsContentType verifies that an explicit Content-Type is not overridden for url.Values bodies.
+func TestClient_FormBodyKeepsContentType(t *testing.T) {
+	server := echoServer(t)
+	client := NewClientBuilder().Build()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	res, err := client.Post(server.URL, header, url.Values{"a": {"1"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Headers().Get("X-Content-Type") != "application/x-www-form-urlencoded; charset=utf-8" {
+		t.Errorf("expected explicit Content-Type to be kept, got %s", res.Headers().Get("X-Content-Type"))
+	}
+}
+
+// TestClient_FormBodyOverridesDefaultContentType verifies that url.Values bodies are not labelled with a client default
+// Content-Type that is not form-compatible, while a form Content-Type passed with the request is kept.
+func TestClient_FormBodyOverridesDefaultContentType(t *testing.T) {
+	server := echoServer(t)
+	client := NewClientBuilder().
+		SetHeaders(http.Header{"Content-Type": {"application/json"}}).
+		Build()
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected string
+	}{
+		{"default", nil, ContentTypeForm},
+		{"explicit", http.Header{"Content-Type": {"application/x-www-form-urlencoded; charset=utf-8"}}, "application/x-www-form-urlencoded; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Post(server.URL, tt.header, url.Values{"a": {"1"}})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if res.Headers().Get("X-Content-Type") != tt.expected {
+				t.Errorf("expected Content-Type %s, got %s", tt.expected, res.Headers().Get("X-Content-Type"))
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package webs
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -13,6 +17,18 @@ const (
 	// defaultConnectionTimeout specifies the default timeout duration for establishing a connection to an HTTP server.
 	defaultConnectionTimeout = 0
 
+	// defaultTLSHandshakeTimeout specifies the default maximum duration of the TLS handshake, matching http.DefaultTransport.
+	defaultTLSHandshakeTimeout = 10 * time.Second
+
+	// defaultIdleConnTimeout specifies the default duration an idle connection is kept open, matching http.DefaultTransport.
+	defaultIdleConnTimeout = 90 * time.Second
+
+	// defaultExpectContinueTimeout specifies the default wait for a 100-continue response, matching http.DefaultTransport.
+	defaultExpectContinueTimeout = 1 * time.Second
+
+	// defaultRequestTimeout specifies the default limit for a whole request, including reading the body. Zero means no limit.
+	defaultRequestTimeout = 0
+
 	// defaultMaxIdleConnsPerHost specifies the default maximum number of idle connections to keep per host in the HTTP client.
 	defaultMaxIdleConnsPerHost = 1
 )
@@ -31,17 +47,48 @@ type Transporter interface {
 type DefaultsRetriever interface {
 	getConnectionTimeout() time.Duration
 	getResponseTimeout() time.Duration
+	getTLSHandshakeTimeout() time.Duration
+	getIdleConnTimeout() time.Duration
+	getExpectContinueTimeout() time.Duration
+	getRequestTimeout() time.Duration
 	getMaxIdleConnsPerHost() int
 }
 
 // ClientBuilder assists in creating customized HTTP clients by configuring headers, timeouts, and connection limits.
 type ClientBuilder struct {
 	headers             http.Header
-	transport           http.Transport
+	transport           http.RoundTripper
+	transportHooks      []func(*http.Transport)
+	proxyURL            *url.URL
+	disableProxy        bool
+	noProxy             []string
+	tls                 tlsSettings
+	cookieJar           http.CookieJar
+	cookieFile          string
+	disableRedirects    bool
+	maxRedirects        int
+	limitRedirects      bool
+	sensitiveHeaders    []string
+	authenticator       Authenticator
 	connectTimeout      time.Duration
 	responseTimeout     time.Duration
+	tlsHandshakeTimeout time.Duration
+	idleConnTimeout     time.Duration
+	keepAlive           time.Duration
+	expectContinue      time.Duration
+	requestTimeout      time.Duration
 	disableTimeouts     bool
 	maxIdleConnsPerHost int
+	maxIdleConns        int
+	maxConnsPerHost     int
+	retryPolicy         RetryPolicy
+	middlewares         []Middleware
+	httpErrors          bool
+	maxBodySize         int64
+	codecs              *CodecRegistry
+	baseURL             *url.URL
+	logger              *slog.Logger
+	errs                []error
 }
 
 // NewClientBuilder creates a new instance of ClientBuilder for configuring customized HTTP clients.
@@ -50,18 +97,38 @@ func NewClientBuilder() *ClientBuilder {
 }
 
 // Build finalizes the ClientBuilder configuration and returns a newly constructed Client instance.
+// Invalid settings recorded while configuring the builder are returned by every request made with the Client.
 func (cb *ClientBuilder) Build() *Client {
 
-	transport := cb.getTransport()
+	transport := cb.getRoundTripper()
+
+	configErr := errors.Join(cb.errs...)
+	jar, err := cb.getCookieJar()
+	if err != nil {
+		configErr = errors.Join(configErr, err)
+	}
+	if err := validatePins(cb.tls.pins, cb.tls.serverName); err != nil {
+		configErr = errors.Join(configErr, err)
+	}
 
 	baseClient := &http.Client{
-		Transport: transport,
-		Timeout:   cb.getConnectionTimeout(),
+		Transport:     transport,
+		Jar:           jar,
+		CheckRedirect: cb.getCheckRedirect(),
 	}
 	client := &Client{
-		client:  baseClient,
-		headers: cb.headers,
+		client:         baseClient,
+		requestTimeout: cb.getRequestTimeout(),
+		headers:        cb.headers,
+		retry:          cb.retryPolicy,
+		httpErrors:     cb.httpErrors,
+		maxBodySize:    cb.maxBodySize,
+		codecs:         cb.getCodecs().clone(),
+		baseURL:        cb.baseURL,
+		authenticator:  cb.authenticator,
+		err:            configErr,
 	}
+	client.handler = chain(client.send, cb.middlewares)
 
 	return client
 }
@@ -73,49 +140,216 @@ func (cb *ClientBuilder) SetHeaders(headers http.Header) *ClientBuilder {
 }
 
 // DisableTimeouts configures the ClientBuilder to enable or disable timeout settings.
+// When disabled, every timeout is zero, including the ones set explicitly. The keep-alive interval is not a timeout
+// and is left unchanged.
 func (cb *ClientBuilder) DisableTimeouts(disable bool) *ClientBuilder {
 	cb.disableTimeouts = disable
 	return cb
 }
 
-// SetConnectTimeout sets the connection timeout duration for the client.
+// SetConnectTimeout sets the connection timeout duration for the client. It is equivalent to SetDialTimeout.
 func (cb *ClientBuilder) SetConnectTimeout(timeout time.Duration) *ClientBuilder {
+	return cb.SetDialTimeout(timeout)
+}
+
+// SetDialTimeout sets the maximum duration for establishing a TCP connection.
+func (cb *ClientBuilder) SetDialTimeout(timeout time.Duration) *ClientBuilder {
 	cb.connectTimeout = timeout
 	return cb
 }
 
-// SetResponseTimeout sets the response timeout duration for the client.
+// SetResponseTimeout sets the response timeout duration for the client. It is equivalent to SetResponseHeaderTimeout.
 func (cb *ClientBuilder) SetResponseTimeout(timeout time.Duration) *ClientBuilder {
+	return cb.SetResponseHeaderTimeout(timeout)
+}
+
+// SetResponseHeaderTimeout sets the maximum duration to wait for the response headers once the request has been
+// written. It does not limit reading the response body.
+func (cb *ClientBuilder) SetResponseHeaderTimeout(timeout time.Duration) *ClientBuilder {
 	cb.responseTimeout = timeout
 	return cb
 }
 
+// SetTLSHandshakeTimeout sets the maximum duration of the TLS handshake. Defaults to 10 seconds.
+func (cb *ClientBuilder) SetTLSHandshakeTimeout(timeout time.Duration) *ClientBuilder {
+	cb.tlsHandshakeTimeout = timeout
+	return cb
+}
+
+// SetIdleConnTimeout sets how long an idle connection is kept in the pool before being closed. Defaults to 90 seconds.
+func (cb *ClientBuilder) SetIdleConnTimeout(timeout time.Duration) *ClientBuilder {
+	cb.idleConnTimeout = timeout
+	return cb
+}
+
+// SetKeepAlive sets the interval between TCP keep-alive probes. Zero uses the operating system default, while a
+// negative value disables keep-alive probes.
+func (cb *ClientBuilder) SetKeepAlive(interval time.Duration) *ClientBuilder {
+	cb.keepAlive = interval
+	return cb
+}
+
+// SetExpectContinueTimeout sets how long to wait for a 100-continue response when the request carries an
+// "Expect: 100-continue" header. Defaults to 1 second.
+func (cb *ClientBuilder) SetExpectContinueTimeout(timeout time.Duration) *ClientBuilder {
+	cb.expectContinue = timeout
+	return cb
+}
+
+// SetRequestTimeout sets the maximum duration of a whole request, including retries and reading the response body.
+// It can be overridden per request with WithRequestTimeout. Streamed bodies are bound by it until they are closed.
+func (cb *ClientBuilder) SetRequestTimeout(timeout time.Duration) *ClientBuilder {
+	cb.requestTimeout = timeout
+	return cb
+}
+
 // SetMaxIdleConnectionsPerHost sets the maximum number of idle connections to keep per-host for the HTTP client.
 func (cb *ClientBuilder) SetMaxIdleConnectionsPerHost(maxIdleConnsPerHost int) *ClientBuilder {
 	cb.maxIdleConnsPerHost = maxIdleConnsPerHost
 	return cb
 }
 
-// getResponseTimeout calculates and returns the appropriate response timeout duration for the HTTP client.
-func (cb *ClientBuilder) getResponseTimeout() time.Duration {
-	if cb.responseTimeout > 0 {
-		return cb.responseTimeout
+// SetMaxIdleConnections sets the maximum number of idle connections kept across all hosts. Zero means no limit.
+func (cb *ClientBuilder) SetMaxIdleConnections(maxIdleConns int) *ClientBuilder {
+	cb.maxIdleConns = maxIdleConns
+	return cb
+}
+
+// SetMaxConnectionsPerHost sets the maximum number of connections per host, including those in use. Zero means no limit.
+func (cb *ClientBuilder) SetMaxConnectionsPerHost(maxConnsPerHost int) *ClientBuilder {
+	cb.maxConnsPerHost = maxConnsPerHost
+	return cb
+}
+
+// SetTransport sets the RoundTripper used to send requests. When it is an *http.Transport, a clone of it is used and
+// the builder's timeout and pool settings that were set explicitly are applied on top. Any other RoundTripper, such as
+// an instrumented wrapper, is used as-is and is responsible for its own settings.
+func (cb *ClientBuilder) SetTransport(transport http.RoundTripper) *ClientBuilder {
+	cb.transport = transport
+	return cb
+}
+
+// ConfigureTransport registers a hook that customises the *http.Transport, for example to tune ForceAttemptHTTP2 or
+// DisableCompression. Hooks run in order before the builder's explicit settings are applied. They are not called when
+// SetTransport is given a RoundTripper other than *http.Transport.
+func (cb *ClientBuilder) ConfigureTransport(configure func(*http.Transport)) *ClientBuilder {
+	cb.transportHooks = append(cb.transportHooks, configure)
+	return cb
+}
+
+// SetRetryPolicy sets the policy used to retry failed requests. Only idempotent methods are retried unless the
+// policy explicitly allows otherwise.
+func (cb *ClientBuilder) SetRetryPolicy(policy RetryPolicy) *ClientBuilder {
+	cb.retryPolicy = policy
+	return cb
+}
+
+// Use appends middleware to the chain wrapped around every request. Middleware runs in the order it was added,
+// the first one being the outermost.
+func (cb *ClientBuilder) Use(middleware ...Middleware) *ClientBuilder {
+	cb.middlewares = append(cb.middlewares, middleware...)
+	return cb
+}
+
+// EnableHTTPErrors configures the client to return an *HTTPError, alongside the Response, for non-2xx status codes.
+func (cb *ClientBuilder) EnableHTTPErrors(enable bool) *ClientBuilder {
+	cb.httpErrors = enable
+	return cb
+}
+
+// SetMaxResponseBodySize sets the maximum number of bytes read into a Response. Larger bodies fail with
+// ErrResponseTooLarge. A non-positive value, the default, disables the limit.
+func (cb *ClientBuilder) SetMaxResponseBodySize(limit int64) *ClientBuilder {
+	cb.maxBodySize = limit
+	return cb
+}
+
+// RegisterCodec registers a codec used to encode request bodies and decode responses for the media types it supports.
+// The JSON and XML codecs are registered by default and can be replaced.
+func (cb *ClientBuilder) RegisterCodec(codec Codec) *ClientBuilder {
+	cb.getCodecs().Register(codec)
+	return cb
+}
+
+// getCodecs returns the codec registry of the builder, creating it with the default codecs on first use.
+func (cb *ClientBuilder) getCodecs() *CodecRegistry {
+	if cb.codecs == nil {
+		cb.codecs = defaultCodecRegistry()
 	}
-	if cb.disableTimeouts {
-		return 0
+	return cb.codecs
+}
+
+// SetBaseURL sets the URL that relative request URLs are resolved against. Relative paths are appended to the base
+// path, so "users" and "/users" both resolve to "https://api.example.com/v1/users" for a base of
+// "https://api.example.com/v1".
+func (cb *ClientBuilder) SetBaseURL(baseURL string) *ClientBuilder {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid base URL: %w", err))
+		return cb
+	}
+	if !parsed.IsAbs() || parsed.Host == "" {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL))
+		return cb
 	}
-	return defaultResponseTimeout
+	cb.baseURL = parsed
+	return cb
+}
+
+// SetLogger sets the logger used to report problems that do not fail a request, such as a client certificate that
+// cannot be reloaded or cookies that cannot be persisted. The default logger of the slog package is used otherwise.
+func (cb *ClientBuilder) SetLogger(logger *slog.Logger) *ClientBuilder {
+	cb.logger = logger
+	return cb
+}
+
+// getLogger returns the logger set with SetLogger, or the default slog logger.
+func (cb *ClientBuilder) getLogger() *slog.Logger {
+	if cb.logger == nil {
+		return slog.Default()
+	}
+	return cb.logger
+}
+
+// getResponseTimeout calculates and returns the appropriate response timeout duration for the HTTP client.
+func (cb *ClientBuilder) getResponseTimeout() time.Duration {
+	return cb.resolveTimeout(cb.responseTimeout, defaultResponseTimeout)
 }
 
 // getConnectionTimeout returns the configured connection timeout duration or the default value if not set.
 func (cb *ClientBuilder) getConnectionTimeout() time.Duration {
-	if cb.connectTimeout > 0 {
-		return cb.connectTimeout
-	}
+	return cb.resolveTimeout(cb.connectTimeout, defaultConnectionTimeout)
+}
+
+// getTLSHandshakeTimeout returns the configured TLS handshake timeout or the default value if not set.
+func (cb *ClientBuilder) getTLSHandshakeTimeout() time.Duration {
+	return cb.resolveTimeout(cb.tlsHandshakeTimeout, defaultTLSHandshakeTimeout)
+}
+
+// getIdleConnTimeout returns the configured idle connection timeout or the default value if not set.
+func (cb *ClientBuilder) getIdleConnTimeout() time.Duration {
+	return cb.resolveTimeout(cb.idleConnTimeout, defaultIdleConnTimeout)
+}
+
+// getExpectContinueTimeout returns the configured expect-continue timeout or the default value if not set.
+func (cb *ClientBuilder) getExpectContinueTimeout() time.Duration {
+	return cb.resolveTimeout(cb.expectContinue, defaultExpectContinueTimeout)
+}
+
+// getRequestTimeout returns the configured total request timeout or the default value if not set.
+func (cb *ClientBuilder) getRequestTimeout() time.Duration {
+	return cb.resolveTimeout(cb.requestTimeout, defaultRequestTimeout)
+}
+
+// resolveTimeout returns zero when timeouts are disabled, the configured timeout when set, or the fallback otherwise.
+func (cb *ClientBuilder) resolveTimeout(timeout, fallback time.Duration) time.Duration {
 	if cb.disableTimeouts {
 		return 0
 	}
-	return defaultConnectionTimeout
+	if timeout > 0 {
+		return timeout
+	}
+	return fallback
 }
 
 // getMaxIdleConnsPerHost returns the maximum number of idle connections per host. If not set, defaults to 1.
@@ -129,10 +363,18 @@ func (cb *ClientBuilder) getMaxIdleConnsPerHost() int {
 // getTransport configures and returns an *http.Transport with custom timeout settings and connection limits.
 func (cb *ClientBuilder) getTransport() *http.Transport {
 	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cb.maxIdleConns,
+		MaxConnsPerHost:       cb.maxConnsPerHost,
 		MaxIdleConnsPerHost:   cb.getMaxIdleConnsPerHost(),
 		ResponseHeaderTimeout: cb.getResponseTimeout(),
+		TLSHandshakeTimeout:   cb.getTLSHandshakeTimeout(),
+		IdleConnTimeout:       cb.getIdleConnTimeout(),
+		ExpectContinueTimeout: cb.getExpectContinueTimeout(),
 		DialContext: (&net.Dialer{
-			Timeout: cb.getConnectionTimeout(),
+			Timeout:   cb.getConnectionTimeout(),
+			KeepAlive: cb.keepAlive,
 		}).DialContext,
 	}
 }
@@ -106,3 +106,63 @@ func TestClientBuilder_DisabledTimeouts(t *testing.T) {
 	}
 
 }
+
+// TestClientBuilder_DisabledTimeoutsOverrideExplicitValues verifies that DisableTimeouts wins over timeouts set explicitly.
+func TestClientBuilder_DisabledTimeoutsOverrideExplicitValues(t *testing.T) {
+	builder := NewClientBuilder().
+		SetDialTimeout(time.Second).
+		SetResponseHeaderTimeout(time.Second).
+		SetTLSHandshakeTimeout(time.Second).
+		SetIdleConnTimeout(time.Second).
+		SetExpectContinueTimeout(time.Second).
+		SetRequestTimeout(time.Second).
+		DisableTimeouts(true)
+
+	getters := map[string]func() time.Duration{
+		"dial":           builder.getConnectionTimeout,
+		"responseHeader": builder.getResponseTimeout,
+		"tlsHandshake":   builder.getTLSHandshakeTimeout,
+		"idleConn":       builder.getIdleConnTimeout,
+		"expectContinue": builder.getExpectContinueTimeout,
+		"request":        builder.getRequestTimeout,
+	}
+	for name, getter := range getters {
+		if timeout := getter(); timeout != 0 {
+			t.Errorf("expected %s timeout to be 0, got %s", name, timeout)
+		}
+	}
+}
+
+// TestClientBuilder_TransportTimeouts verifies that every timeout is applied to its own transport setting
+// and that the total request timeout is not used as the http.Client timeout.
+func TestClientBuilder_TransportTimeouts(t *testing.T) {
+	builder := NewClientBuilder().
+		SetDialTimeout(1 * time.Second).
+		SetResponseHeaderTimeout(2 * time.Second).
+		SetTLSHandshakeTimeout(3 * time.Second).
+		SetIdleConnTimeout(4 * time.Second).
+		SetExpectContinueTimeout(5 * time.Second).
+		SetRequestTimeout(6 * time.Second)
+
+	transport := builder.getTransport()
+	if transport.ResponseHeaderTimeout != 2*time.Second {
+		t.Errorf("expected response header timeout to be 2s, got %s", transport.ResponseHeaderTimeout)
+	}
+	if transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("expected TLS handshake timeout to be 3s, got %s", transport.TLSHandshakeTimeout)
+	}
+	if transport.IdleConnTimeout != 4*time.Second {
+		t.Errorf("expected idle connection timeout to be 4s, got %s", transport.IdleConnTimeout)
+	}
+	if transport.ExpectContinueTimeout != 5*time.Second {
+		t.Errorf("expected expect-continue timeout to be 5s, got %s", transport.ExpectContinueTimeout)
+	}
+
+	client := builder.Build()
+	if client.client.Timeout != 0 {
+		t.Errorf("expected http.Client timeout to be 0, got %s", client.client.Timeout)
+	}
+	if client.requestTimeout != 6*time.Second {
+		t.Errorf("expected request timeout to be 6s, got %s", client.requestTimeout)
+	}
+}
@@ -0,0 +1,198 @@
+package webs
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCertificatePinMismatch is matched by a CertificatePinError.
+var ErrCertificatePinMismatch = errors.New("certificate pin mismatch")
+
+// CertificatePinError is returned when none of the certificates presented by a pinned host matches its pins.
+type CertificatePinError struct {
+	Host string
+}
+
+// Error returns a description of the pin mismatch including the host.
+func (e *CertificatePinError) Error() string {
+	return fmt.Sprintf("%s for host %s", ErrCertificatePinMismatch, e.Host)
+}
+
+// Unwrap returns ErrCertificatePinMismatch so the error can be matched with errors.Is.
+func (e *CertificatePinError) Unwrap() error {
+	return ErrCertificatePinMismatch
+}
+
+// SPKIHash returns the base64-encoded SHA-256 hash of the certificate's subject public key info, as used by PinCertificate.
+func SPKIHash(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// PinCertificate restricts the certificates accepted for host to those whose chain contains a public key matching one
+// of the SPKI hashes, as returned by SPKIHash and optionally prefixed with "sha256/". The host is matched against the
+// server name used for the handshake. TLS does not send IP addresses as server names, so a host reached by IP address
+// is only pinned when the same address is passed to SetServerName; any other IP address pin fails every request.
+// When certificate verification is disabled with InsecureSkipVerify, only the leaf certificate is matched.
+// A mismatch fails the handshake with a *CertificatePinError.
+func (cb *ClientBuilder) PinCertificate(host string, spkiHashes ...string) *ClientBuilder {
+	if cb.tls.pins == nil {
+		cb.tls.pins = make(map[string][]string)
+	}
+	host = strings.Trim(strings.ToLower(host), "[]")
+	for _, hash := range spkiHashes {
+		cb.tls.pins[host] = append(cb.tls.pins[host], strings.TrimPrefix(hash, "sha256/"))
+	}
+	return cb
+}
+
+// WatchClientCertificateFiles presents the certificate and private key stored in the PEM files to servers requesting
+// mutual TLS, reloading them whenever either file changes on disk. Rotated certificates are used for new connections
+// without rebuilding the client. If a reload fails, the last valid certificate is kept and a warning is logged.
+func (cb *ClientBuilder) WatchClientCertificateFiles(certFile, keyFile string) *ClientBuilder {
+	reloader, err := newCertificateReloader(certFile, keyFile)
+	if err != nil {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid client certificate: %w", err))
+		return cb
+	}
+	cb.tls.reloader = reloader
+	return cb
+}
+
+// validatePins reports the IP address pins that cannot be enforced because the handshake does not carry the address.
+func validatePins(pins map[string][]string, serverName string) error {
+	var errs []error
+	for host := range pins {
+		if net.ParseIP(host) != nil && !strings.EqualFold(host, serverName) {
+			errs = append(errs, fmt.Errorf("invalid certificate pin for %s: pinning an IP address requires SetServerName(%q)", host, host))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// verifyPins returns a VerifyConnection callback checking the pins of the host being connected to. The server name
+// set on the builder takes precedence, as the connection state omits it when it is an IP address.
+func verifyPins(pins map[string][]string, serverName string) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		host := strings.ToLower(state.ServerName)
+		if serverName != "" {
+			host = strings.ToLower(serverName)
+		}
+		expected, ok := pins[host]
+		if !ok {
+			return nil
+		}
+
+		// Without verified chains the peer controls every certificate it presents, so only its leaf can be trusted
+		// to hold the key used in the handshake.
+		chains := state.VerifiedChains
+		if len(chains) == 0 && len(state.PeerCertificates) > 0 {
+			chains = [][]*x509.Certificate{state.PeerCertificates[:1]}
+		}
+		for _, chain := range chains {
+			for _, certificate := range chain {
+				hash := SPKIHash(certificate)
+				for _, pin := range expected {
+					if hash == pin {
+						return nil
+					}
+				}
+			}
+		}
+		return &CertificatePinError{Host: host}
+	}
+}
+
+// certificateReloader serves a client certificate loaded from files, reloading it when their modification time changes.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// newCertificateReloader loads the certificate from the files, failing when they cannot be read or parsed.
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	reloader := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// withLogger returns a reloader of the same files, starting from the loaded certificate, that logs reload failures
+// with logger. Each built client gets its own copy, so clients never share mutable state.
+func (r *certificateReloader) withLogger(logger *slog.Logger) *certificateReloader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &certificateReloader{
+		certFile:    r.certFile,
+		keyFile:     r.keyFile,
+		logger:      logger,
+		certificate: r.certificate,
+		certModTime: r.certModTime,
+		keyModTime:  r.keyModTime,
+	}
+}
+
+// GetClientCertificate returns the current certificate, reloading it first when the files have changed.
+func (r *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.changed() {
+		if err := r.reload(); err != nil {
+			r.logger.Warn("webs: failed to reload client certificate, keeping the previous one", "error", err)
+		}
+	}
+	return r.certificate, nil
+}
+
+// changed reports whether either file has a modification time different from the one of the loaded certificate.
+func (r *certificateReloader) changed() bool {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return false
+	}
+	return !certInfo.ModTime().Equal(r.certModTime) || !keyInfo.ModTime().Equal(r.keyModTime)
+}
+
+// reload reads and parses the files, replacing the current certificate on success.
+func (r *certificateReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.certificate = &certificate
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return nil
+}
@@ -0,0 +1,266 @@
+package webs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestClientBuilder_WatchClientCertificateFiles verifies that a rotated client certificate is used for new
+// connections without rebuilding the client, and that reload failures are logged with the builder's logger.
+func TestClientBuilder_WatchClientCertificateFiles(t *testing.T) {
+	ca := newTestCA(t)
+	server := newMutualTLSServer(t, ca)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	writePair := func(commonName string, modTime time.Time) {
+		certPEM, keyPEM := ca.issue(t, commonName, x509.ExtKeyUsageClientAuth)
+		for path, content := range map[string][]byte{certFile: certPEM, keyFile: keyPEM} {
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+	}
+
+	writePair("client-1", time.Now().Add(-time.Minute))
+	var logs bytes.Buffer
+	builder := NewClientBuilder().
+		AddRootCAs(ca.pem).
+		SetServerName("api.internal").
+		SetLogger(slog.New(slog.NewTextHandler(&logs, nil))).
+		WatchClientCertificateFiles(certFile, keyFile)
+	client := builder.Build()
+
+	// Building another client from the same builder must not race with the handshakes of the first one.
+	built := make(chan *Client)
+	go func() {
+		built <- builder.Build()
+	}()
+	res, err := client.Get(server.URL, nil)
+	<-built
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "client-1" {
+		t.Errorf("expected client-1, got %s", res.String())
+	}
+
+	writePair("client-2", time.Now())
+	client.client.CloseIdleConnections()
+
+	res, err = client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "client-2" {
+		t.Errorf("expected client-2 after rotation, got %s", res.String())
+	}
+
+	if err := os.WriteFile(certFile, []byte("corrupted"), 0o600); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	client.client.CloseIdleConnections()
+
+	res, err = client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected the previous certificate to be kept, got %v", err)
+	}
+	if res.String() != "client-2" {
+		t.Errorf("expected client-2 to be kept, got %s", res.String())
+	}
+	if !strings.Contains(logs.String(), "failed to reload client certificate") {
+		t.Errorf("expected the reload failure to be logged, got %q", logs.String())
+	}
+}
+
+// TestClientBuilder_WatchClientCertificateFilesMissing verifies that missing files are reported by every request.
+func TestClientBuilder_WatchClientCertificateFilesMissing(t *testing.T) {
+	dir := t.TempDir()
+	client := NewClientBuilder().
+		WatchClientCertificateFiles(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")).
+		Build()
+
+	if _, err := client.Get("https://server.example", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+// TestClientBuilder_PinCertificate verifies that connections succeed only when the server key matches a pin.
+func TestClientBuilder_PinCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "api.internal", x509.ExtKeyUsageServerAuth)
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	defer server.Close()
+
+	block, _ := pem.Decode(certPEM)
+	leaf, _ := x509.ParseCertificate(block.Bytes)
+
+	t.Run("leafPin", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			SetServerName("api.internal").
+			PinCertificate("api.internal", "sha256/"+SPKIHash(leaf)).
+			Build()
+		if _, err := client.Get(server.URL, nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("caPin", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			SetServerName("api.internal").
+			PinCertificate("API.internal", SPKIHash(ca.certificate)).
+			Build()
+		if _, err := client.Get(server.URL, nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		other := newTestCA(t)
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			SetServerName("api.internal").
+			PinCertificate("api.internal", SPKIHash(other.certificate)).
+			Build()
+
+		_, err := client.Get(server.URL, nil)
+		if !errors.Is(err, ErrCertificatePinMismatch) {
+			t.Fatalf("expected ErrCertificatePinMismatch, got %v", err)
+		}
+		var pinErr *CertificatePinError
+		if !errors.As(err, &pinErr) || pinErr.Host != "api.internal" {
+			t.Errorf("expected *CertificatePinError for api.internal, got %v", err)
+		}
+	})
+
+	t.Run("unpinnedHost", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			SetServerName("api.internal").
+			PinCertificate("other.internal", "invalid").
+			Build()
+		if _, err := client.Get(server.URL, nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
+
+// TestClientBuilder_PinCertificateInsecureSkipVerify verifies that, without chain verification, the pin is only
+// matched against the leaf, so a server cannot pass it by appending the public pinned certificate to its own chain.
+func TestClientBuilder_PinCertificateInsecureSkipVerify(t *testing.T) {
+	ca := newTestCA(t)
+	pinnedPEM, pinnedKeyPEM := ca.issue(t, "api.internal", x509.ExtKeyUsageServerAuth)
+	pinned, err := tls.X509KeyPair(pinnedPEM, pinnedKeyPEM)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	block, _ := pem.Decode(pinnedPEM)
+	pinnedCert, _ := x509.ParseCertificate(block.Bytes)
+
+	attackerPEM, attackerKeyPEM := newTestCA(t).issue(t, "api.internal", x509.ExtKeyUsageServerAuth)
+	attacker, err := tls.X509KeyPair(attackerPEM, attackerKeyPEM)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	attacker.Certificate = append(attacker.Certificate, pinned.Certificate[0])
+
+	tests := []struct {
+		name        string
+		certificate tls.Certificate
+		mismatch    bool
+	}{
+		{"pinnedLeaf", pinned, false},
+		{"pinnedCertificateAppended", attacker, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{tt.certificate}}
+			server.StartTLS()
+			defer server.Close()
+
+			client := NewClientBuilder().
+				InsecureSkipVerify(true).
+				SetServerName("api.internal").
+				PinCertificate("api.internal", SPKIHash(pinnedCert)).
+				Build()
+
+			_, err := client.Get(server.URL, nil)
+			if tt.mismatch && !errors.Is(err, ErrCertificatePinMismatch) {
+				t.Errorf("expected ErrCertificatePinMismatch, got %v", err)
+			}
+			if !tt.mismatch && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestClientBuilder_PinCertificateIPAddress verifies that a pin on a host reached by IP address is enforced when the
+// address is the server name, and fails every request otherwise instead of being silently ignored.
+func TestClientBuilder_PinCertificateIPAddress(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	rootCAs := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	t.Run("mismatch", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(rootCAs).
+			SetServerName("127.0.0.1").
+			PinCertificate("127.0.0.1", "bogus").
+			Build()
+
+		_, err := client.Get(server.URL, nil)
+		if !errors.Is(err, ErrCertificatePinMismatch) {
+			t.Errorf("expected ErrCertificatePinMismatch, got %v", err)
+		}
+	})
+
+	t.Run("match", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(rootCAs).
+			SetServerName("127.0.0.1").
+			PinCertificate("127.0.0.1", SPKIHash(server.Certificate())).
+			Build()
+		if _, err := client.Get(server.URL, nil); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("withoutServerName", func(t *testing.T) {
+		for _, host := range []string{"127.0.0.1", "[::1]"} {
+			client := NewClientBuilder().
+				AddRootCAs(rootCAs).
+				PinCertificate(host, SPKIHash(server.Certificate())).
+				Build()
+			if _, err := client.Get(server.URL, nil); err == nil {
+				t.Errorf("expected error for pin on %s, got nil", host)
+			}
+		}
+	})
+}
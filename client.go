@@ -1,61 +1,207 @@
 package webs
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"github.com/madalinpopa/webs/internal/utils"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 // Client represents a customizable HTTP client built with the help of ClientBuilder.
 type Client struct {
-	client  *http.Client
-	headers http.Header
+	client         *http.Client
+	headers        http.Header
+	retry          RetryPolicy
+	handler        Handler
+	httpErrors     bool
+	maxBodySize    int64
+	codecs         *CodecRegistry
+	baseURL        *url.URL
+	err            error
+	requestTimeout time.Duration
+	authenticator  Authenticator
 }
 
 // ExecuteRequest sends an HTTP request with the specified method, URL, headers, and body, then returns the response.
 func (c *Client) ExecuteRequest(method, url string, headers http.Header, body interface{}) (*Response, error) {
-	allHeaders := utils.MergeHeaders(c.headers, headers)
+	return c.ExecuteRequestContext(context.Background(), method, url, headers, body)
+}
+
+// ExecuteRequestContext sends an HTTP request bound to ctx with the specified method, URL, headers, and body.
+// Relative URLs are resolved against the base URL of the client.
+// Cancelling ctx aborts the request at any stage, including dialing and reading the response body.
+func (c *Client) ExecuteRequestContext(ctx context.Context, method, url string, headers http.Header, body interface{}) (*Response, error) {
+	return c.execute(ctx, method, url, utils.MergeHeaders(c.headers, headers), body)
+}
 
-	requestBody, err := utils.GetRequestBody(allHeaders.Get("Content-Type"), body)
+// execute builds and sends a request with headers that already include the client defaults.
+func (c *Client) execute(ctx context.Context, method, url string, allHeaders http.Header, body interface{}) (*Response, error) {
+	url, err := resolveURL(c.baseURL, url)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	requestBody, err := c.encodeBody(allHeaders, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, requestBody)
 	if err != nil {
 		return nil, errors.New("failed to create request")
 	}
 
 	request.Header = allHeaders
 
-	response, err := c.client.Do(request)
-	if err != nil {
-		return nil, err
+	return c.handle(request)
+}
+
+// handle runs the request through the middleware chain, falling back to send when no chain has been built.
+// Requests fail with the configuration error recorded by the ClientBuilder, if any.
+func (c *Client) handle(request *http.Request) (*Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.handler == nil {
+		return c.send(request)
+	}
+	response, err := c.handler(request)
+	if response != nil && response.request == nil {
+		response.request = request
 	}
+	return response, err
+}
+
+// send dispatches the request through the underlying http.Client, retrying it according to the client's retry policy,
+// and buffers the final response into a Response. Streaming requests keep the body open in the Response instead.
+// When HTTP errors are enabled, a non-2xx response is returned together with an *HTTPError.
+func (c *Client) send(request *http.Request) (*Response, error) {
+	request, cancel := c.withRequestTimeout(request)
 
-	responseBody, err := io.ReadAll(response.Body)
+	response, attempts, err := c.retry.execute(c.attempt, request)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
 
-		}
-	}(response.Body)
 	customResponse := Response{
 		status:     response.Status,
 		statusCode: response.StatusCode,
 		headers:    response.Header,
-		body:       responseBody,
+		attempts:   attempts,
+		redirects:  redirectChain(response),
+		request:    request,
+		codecs:     c.codecs,
+	}
+
+	failed := c.httpErrors && !isSuccess(response.StatusCode)
+	streaming := isStreaming(request.Context())
+	if streaming && !failed {
+		customResponse.stream = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+		return &customResponse, nil
+	}
+	defer cancel()
+
+	if streaming {
+		customResponse.body, err = readPartialBody(response.Body)
+	} else {
+		customResponse.body, err = readBody(response, c.getMaxBodySize(request.Context()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if failed {
+		return &customResponse, newHTTPError(request, &customResponse)
 	}
 	return &customResponse, nil
 }
 
+// withRequestTimeout binds the request to the total request timeout, preferring a per-request override.
+// The returned cancel function must be called once the response body has been consumed.
+func (c *Client) withRequestTimeout(request *http.Request) (*http.Request, context.CancelFunc) {
+	timeout := c.requestTimeout
+	if override, ok := requestTimeoutFromContext(request.Context()); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return request, func() {}
+	}
+	ctx, cancel := context.WithTimeout(request.Context(), timeout)
+	return request.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases the context of a streamed request when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the context bound to the request.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// getCodecs returns the codec registry of the client, or the default registry when none has been configured.
+func (c *Client) getCodecs() *CodecRegistry {
+	if c.codecs == nil {
+		return defaultCodecRegistry()
+	}
+	return c.codecs
+}
+
+// getMaxBodySize returns the response body limit for the request bound to ctx, preferring a per-request override.
+func (c *Client) getMaxBodySize(ctx context.Context) int64 {
+	if limit, ok := maxBodySizeFromContext(ctx); ok {
+		return limit
+	}
+	return c.maxBodySize
+}
+
+// readBody reads and closes the response body. When limit is positive, a body declaring or exceeding more than limit
+// bytes is rejected with a *ResponseTooLargeError instead of being truncated.
+func readBody(response *http.Response, limit int64) ([]byte, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	if limit <= 0 {
+		return io.ReadAll(response.Body)
+	}
+	if response.ContentLength > limit {
+		return nil, &ResponseTooLargeError{Limit: limit, Size: response.ContentLength}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit, Size: int64(len(body))}
+	}
+	return body, nil
+}
+
+// readPartialBody reads the portion of the body kept by HTTPError and closes it,
+// which avoids draining a failed stream that may be arbitrarily large.
+func readPartialBody(body io.ReadCloser) ([]byte, error) {
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(body)
+
+	return io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+}
+
+// Do sends the given request as-is, keeping its body, context, host, trailers, and GetBody, with the client's default
+// headers merged in. The request runs through the same pipeline as ExecuteRequest and its response is buffered.
 func (c *Client) Do(req *http.Request) (*Response, error) {
-	return c.ExecuteRequest(req.Method, req.URL.String(), req.Header, nil)
+	request := req.Clone(req.Context())
+	request.Header = utils.MergeHeaders(c.headers, req.Header)
+	return c.handle(request)
 }
 
 // Get sends an HTTP GET request to the specified URL with optional headers and returns the response.
@@ -63,22 +209,47 @@ func (c *Client) Get(url string, headers http.Header) (*Response, error) {
 	return c.ExecuteRequest(http.MethodGet, url, headers, nil)
 }
 
+// GetContext sends an HTTP GET request bound to ctx to the specified URL with optional headers and returns the response.
+func (c *Client) GetContext(ctx context.Context, url string, headers http.Header) (*Response, error) {
+	return c.ExecuteRequestContext(ctx, http.MethodGet, url, headers, nil)
+}
+
 // Post sends an HTTP POST request to the specified URL with provided headers and body, and returns the response.
 func (c *Client) Post(url string, headers http.Header, body interface{}) (*Response, error) {
 	return c.ExecuteRequest(http.MethodPost, url, headers, body)
 }
 
+// PostContext sends an HTTP POST request bound to ctx to the specified URL with provided headers and body, and returns the response.
+func (c *Client) PostContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error) {
+	return c.ExecuteRequestContext(ctx, http.MethodPost, url, headers, body)
+}
+
 // Put sends an HTTP PUT request to the specified URL with provided headers and body, and returns the response.
 func (c *Client) Put(url string, headers http.Header, body interface{}) (*Response, error) {
 	return c.ExecuteRequest(http.MethodPut, url, headers, body)
 }
 
+// PutContext sends an HTTP PUT request bound to ctx to the specified URL with provided headers and body, and returns the response.
+func (c *Client) PutContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error) {
+	return c.ExecuteRequestContext(ctx, http.MethodPut, url, headers, body)
+}
+
 // Patch sends an HTTP PATCH request to the specified URL with provided headers and body, then returns the response.
 func (c *Client) Patch(url string, headers http.Header, body interface{}) (*Response, error) {
 	return c.ExecuteRequest(http.MethodPatch, url, headers, body)
 }
 
+// PatchContext sends an HTTP PATCH request bound to ctx to the specified URL with provided headers and body, then returns the response.
+func (c *Client) PatchContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error) {
+	return c.ExecuteRequestContext(ctx, http.MethodPatch, url, headers, body)
+}
+
 // Delete sends an HTTP DELETE request to the specified URL with optional headers and returns the response.
 func (c *Client) Delete(url string, headers http.Header) (*Response, error) {
 	return c.ExecuteRequest(http.MethodDelete, url, headers, nil)
 }
+
+// DeleteContext sends an HTTP DELETE request bound to ctx to the specified URL with optional headers and returns the response.
+func (c *Client) DeleteContext(ctx context.Context, url string, headers http.Header) (*Response, error) {
+	return c.ExecuteRequestContext(ctx, http.MethodDelete, url, headers, nil)
+}
@@ -1,9 +1,15 @@
 package webs
 
 import (
+	"context"
+	"errors"
 	"github.com/h2non/gock"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestClient_Build verifies that the ClientBuilder successfully creates a non-nil Client instance when Build is invoked.
@@ -198,3 +204,181 @@ func TestClient_Do(t *testing.T) {
 	}
 
 }
+
+// TestClient_GetContext verifies that GetContext sends the request and returns the expected response when the context is live.
+func TestClient_GetContext(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/").
+		Reply(200).
+		JSON(map[string]string{"hello": "world"})
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	res, err := client.GetContext(context.Background(), "https://server.com", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.statusCode != 200 {
+		t.Errorf("expected status code 200, got %d", res.statusCode)
+	}
+}
+
+// TestClient_ExecuteRequestContextCanceled verifies that a cancelled context aborts the request before it is sent.
+func TestClient_ExecuteRequestContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected request not to reach the server")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClientBuilder().Build()
+	_, err := client.ExecuteRequestContext(ctx, http.MethodGet, server.URL, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestClient_ExecuteRequestContextBodyRead verifies that a context deadline aborts a response body that is still being read.
+func TestClient_ExecuteRequestContextBodyRead(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClientBuilder().Build()
+	_, err := client.GetContext(ctx, server.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestClient_DoKeepsRequest verifies that Do sends the body, host override, and context of a hand-built request
+// and merges the client's default headers.
+func TestClient_DoKeepsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Host", r.Host)
+		w.Header().Set("X-Default", r.Header.Get("X-Default"))
+		w.Header().Set("X-Custom", r.Header.Get("X-Custom"))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	headers := http.Header{}
+	headers.Set("X-Default", "default")
+	client := NewClientBuilder().SetHeaders(headers).Build()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	req.Host = "api.internal"
+	req.Header.Set("X-Custom", "custom")
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "payload" {
+		t.Errorf("expected body payload, got %s", res.String())
+	}
+	if res.Headers().Get("X-Host") != "api.internal" {
+		t.Errorf("expected host api.internal, got %s", res.Headers().Get("X-Host"))
+	}
+	if res.Headers().Get("X-Default") != "default" || res.Headers().Get("X-Custom") != "custom" {
+		t.Errorf("expected default and custom headers, got %v", res.Headers())
+	}
+	if req.Header.Get("X-Default") != "" {
+		t.Error("expected the caller's request headers to be left untouched")
+	}
+}
+
+// TestClient_DoKeepsContext verifies that Do honours the context of the request.
+func TestClient_DoKeepsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected request not to reach the server")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	client := NewClientBuilder().Build()
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// slowBodyServer returns a test server that sends the headers immediately and finishes the body after delay.
+func slowBodyServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("start-"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(delay):
+			_, _ = w.Write([]byte("end"))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClient_RequestTimeout verifies that the total request timeout covers reading the body and can be overridden per request.
+func TestClient_RequestTimeout(t *testing.T) {
+	server := slowBodyServer(t, 100*time.Millisecond)
+	client := NewClientBuilder().SetRequestTimeout(30 * time.Millisecond).Build()
+
+	if _, err := client.Get(server.URL, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	res, err := client.GetContext(WithRequestTimeout(context.Background(), time.Second), server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "start-end" {
+		t.Errorf("expected full body, got %s", res.String())
+	}
+
+	res, err = client.R().Timeout(time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "start-end" {
+		t.Errorf("expected full body, got %s", res.String())
+	}
+}
+
+// TestClient_DialTimeoutDoesNotLimitBody verifies that a short dial timeout no longer cuts off slow body reads.
+func TestClient_DialTimeoutDoesNotLimitBody(t *testing.T) {
+	server := slowBodyServer(t, 50*time.Millisecond)
+	client := NewClientBuilder().SetConnectTimeout(10 * time.Millisecond).Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "start-end" {
+		t.Errorf("expected full body, got %s", res.String())
+	}
+}
@@ -0,0 +1,143 @@
+package webs
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+const (
+	// ContentTypeJSON represents the MIME type for JSON data: "application/json".
+	ContentTypeJSON = "application/json"
+
+	// ContentTypeXML represents the MIME type for XML data: "application/xml".
+	ContentTypeXML = "application/xml"
+)
+
+// Codec marshals and unmarshals bodies for the media types it supports.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	MediaTypes() []string
+}
+
+// JSONCodec encodes and decodes JSON bodies using encoding/json.
+type JSONCodec struct{}
+
+// Marshal returns the JSON encoding of v.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal parses the JSON-encoded data into v.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// MediaTypes returns the media types handled by the JSON codec.
+func (JSONCodec) MediaTypes() []string {
+	return []string{ContentTypeJSON, "text/json"}
+}
+
+// XMLCodec encodes and decodes XML bodies using encoding/xml.
+type XMLCodec struct{}
+
+// Marshal returns the XML encoding of v.
+func (XMLCodec) Marshal(v interface{}) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+// Unmarshal parses the XML-encoded data into v.
+func (XMLCodec) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// MediaTypes returns the media types handled by the XML codec.
+func (XMLCodec) MediaTypes() []string {
+	return []string{ContentTypeXML, "text/xml"}
+}
+
+// CodecRegistry maps media types to the codecs able to handle them.
+// Structured syntax suffixes such as "+json" and "+xml" resolve to the codec registered for the base type.
+type CodecRegistry struct {
+	codecs map[string]Codec
+}
+
+// NewCodecRegistry creates a CodecRegistry holding the given codecs.
+func NewCodecRegistry(codecs ...Codec) *CodecRegistry {
+	registry := &CodecRegistry{codecs: make(map[string]Codec)}
+	for _, codec := range codecs {
+		registry.Register(codec)
+	}
+	return registry
+}
+
+// defaultCodecRegistry returns a registry holding the JSON and XML codecs.
+func defaultCodecRegistry() *CodecRegistry {
+	return NewCodecRegistry(JSONCodec{}, XMLCodec{})
+}
+
+// Register adds the codec for every media type it supports, replacing any codec previously registered for them.
+func (r *CodecRegistry) Register(codec Codec) {
+	for _, mediaType := range codec.MediaTypes() {
+		r.codecs[strings.ToLower(mediaType)] = codec
+	}
+}
+
+// Lookup returns the codec for the given Content-Type value. Parameters such as charset are ignored, and a
+// structured syntax suffix falls back to the codec of its base type, e.g. "application/problem+json" to JSON.
+func (r *CodecRegistry) Lookup(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	if codec, ok := r.codecs[mediaType]; ok {
+		return codec, true
+	}
+
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		switch mediaType[i+1:] {
+		case "json":
+			codec, ok := r.codecs[ContentTypeJSON]
+			return codec, ok
+		case "xml":
+			codec, ok := r.codecs[ContentTypeXML]
+			return codec, ok
+		}
+	}
+	return nil, false
+}
+
+// clone returns a copy of the registry that can be modified independently.
+func (r *CodecRegistry) clone() *CodecRegistry {
+	registry := &CodecRegistry{codecs: make(map[string]Codec, len(r.codecs))}
+	for mediaType, codec := range r.codecs {
+		registry.codecs[mediaType] = codec
+	}
+	return registry
+}
+
+// marshal encodes body with the codec matching contentType, defaulting to JSON when the type is empty or unknown.
+func (r *CodecRegistry) marshal(contentType string, body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	if codec, ok := r.Lookup(contentType); ok {
+		return codec.Marshal(body)
+	}
+	return JSONCodec{}.Marshal(body)
+}
+
+// unmarshal decodes data into target with the codec matching contentType, defaulting to JSON when the type is empty.
+func (r *CodecRegistry) unmarshal(contentType string, data []byte, target interface{}) error {
+	if contentType == "" {
+		return JSONCodec{}.Unmarshal(data, target)
+	}
+	codec, ok := r.Lookup(contentType)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, contentType)
+	}
+	return codec.Unmarshal(data, target)
+}
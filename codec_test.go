@@ -0,0 +1,156 @@
+package webs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCodecRegistry_Lookup verifies that content types are matched by media type, ignoring parameters and honouring suffixes.
+func TestCodecRegistry_Lookup(t *testing.T) {
+	registry := defaultCodecRegistry()
+
+	tests := []struct {
+		contentType string
+		want        Codec
+	}{
+		{"application/json", JSONCodec{}},
+		{"Application/JSON; charset=utf-8", JSONCodec{}},
+		{"application/problem+json", JSONCodec{}},
+		{"application/xml", XMLCodec{}},
+		{"text/xml; charset=utf-8", XMLCodec{}},
+		{"application/atom+xml", XMLCodec{}},
+		{"text/plain", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			codec, ok := registry.Lookup(tt.contentType)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("expected no codec, got %T", codec)
+				}
+				return
+			}
+			if !ok || codec != tt.want {
+				t.Errorf("expected %T, got %T", tt.want, codec)
+			}
+		})
+	}
+}
+
+// TestCodecRegistry_Marshal tests request body encoding for different content types and request bodies.
+func TestCodecRegistry_Marshal(t *testing.T) {
+	registry := defaultCodecRegistry()
+
+	t.Run("nobBodyNilResponse", func(t *testing.T) {
+		body, err := registry.marshal("", nil)
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		if body != nil {
+			t.Errorf("expected body to be nil, got %s", body)
+		}
+	})
+
+	t.Run("bodyWithJsonResponse", func(t *testing.T) {
+		body, err := registry.marshal("application/json; charset=utf-8", []string{"foo", "bar"})
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		expectedBody := `["foo","bar"]`
+		if string(body) != expectedBody {
+			t.Errorf("expected %s, got %s", expectedBody, body)
+		}
+	})
+
+	t.Run("bodyWithXmlResponse", func(t *testing.T) {
+		body, err := registry.marshal("application/xml", []string{"foo", "bar"})
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		expectedBody := `<string>foo</string><string>bar</string>`
+		if string(body) != expectedBody {
+			t.Errorf("expected %s, got %s", expectedBody, body)
+		}
+	})
+
+	t.Run("defaultResponse", func(t *testing.T) {
+		body, err := registry.marshal("", []string{"foo", "bar"})
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		expectedBody := `["foo","bar"]`
+		if string(body) != expectedBody {
+			t.Errorf("expected %s, got %s", expectedBody, body)
+		}
+	})
+}
+
+// shout is a test body encoded by upperCodec.
+type shout struct {
+	Text string
+}
+
+// upperCodec is a test codec that prefixes shout bodies for the text/x-upper media type.
+type upperCodec struct{}
+
+func (upperCodec) Marshal(v interface{}) ([]byte, error) {
+	return []byte("UPPER:" + v.(shout).Text), nil
+}
+
+func (upperCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*string) = string(data)
+	return nil
+}
+
+func (upperCodec) MediaTypes() []string {
+	return []string{"text/x-upper"}
+}
+
+// TestClientBuilder_RegisterCodec verifies that a registered codec encodes requests and decodes responses.
+func TestClientBuilder_RegisterCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "UPPER:hello" {
+			t.Errorf("expected body UPPER:hello, got %s", body)
+		}
+		w.Header().Set("Content-Type", "text/x-upper")
+		_, _ = w.Write([]byte("world"))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().RegisterCodec(upperCodec{}).Build()
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/x-upper")
+
+	res, err := client.Post(server.URL, header, shout{Text: "hello"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var content string
+	if err := res.Decode(&content); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != "world" {
+		t.Errorf("expected world, got %s", content)
+	}
+
+	if _, ok := defaultCodecRegistry().Lookup("text/x-upper"); ok {
+		t.Error("expected default registry to be left untouched")
+	}
+}
+
+// TestResponse_DecodeUnsupported verifies that decoding a body without a matching codec fails with ErrUnsupportedMediaType.
+func TestResponse_DecodeUnsupported(t *testing.T) {
+	resp := NewResponse(200, http.Header{"Content-Type": []string{"text/plain"}}, []byte("hello"))
+
+	var target string
+	if err := resp.Decode(&target); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("expected ErrUnsupportedMediaType, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package webs
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// contextKey is the type of the keys used to store per-request options in a context.
+type contextKey int
+
+const (
+	// streamKey marks a request whose response body must be handed to the caller instead of being buffered.
+	streamKey contextKey = iota
+
+	// maxBodySizeKey holds a per-request override of the maximum response body size.
+	maxBodySizeKey
+
+	// requestTimeoutKey holds a per-request override of the total request timeout.
+	requestTimeoutKey
+
+	// authenticatorKey holds a per-request override of the client's Authenticator.
+	authenticatorKey
+
+	// transportKey holds the transport of the client sending the request, for authenticators making their own requests.
+	transportKey
+)
+
+// withStreaming returns a copy of ctx that marks the request as streaming.
+func withStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamKey, true)
+}
+
+// isStreaming reports whether the request bound to ctx expects a streamed response body.
+func isStreaming(ctx context.Context) bool {
+	streaming, _ := ctx.Value(streamKey).(bool)
+	return streaming
+}
+
+// WithMaxResponseBodySize returns a copy of ctx that overrides the client's maximum response body size for requests
+// bound to it. A non-positive limit removes the limit for those requests.
+func WithMaxResponseBodySize(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, maxBodySizeKey, limit)
+}
+
+// maxBodySizeFromContext returns the maximum response body size stored in ctx, if any.
+func maxBodySizeFromContext(ctx context.Context) (int64, bool) {
+	limit, ok := ctx.Value(maxBodySizeKey).(int64)
+	return limit, ok
+}
+
+// WithRequestTimeout returns a copy of ctx that overrides the client's total request timeout for requests bound to it.
+// Unlike a context deadline, the override can also extend the timeout configured on the client. A non-positive timeout
+// removes the limit for those requests.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey, timeout)
+}
+
+// requestTimeoutFromContext returns the total request timeout stored in ctx, if any.
+func requestTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(requestTimeoutKey).(time.Duration)
+	return timeout, ok
+}
+
+// WithAuthenticator returns a copy of ctx that overrides the client's Authenticator for requests bound to it.
+// Use NoAuth to send those requests without credentials.
+func WithAuthenticator(ctx context.Context, authenticator Authenticator) context.Context {
+	return context.WithValue(ctx, authenticatorKey, authenticator)
+}
+
+// authenticatorFromContext returns the Authenticator stored in ctx, if any.
+func authenticatorFromContext(ctx context.Context) (Authenticator, bool) {
+	authenticator, ok := ctx.Value(authenticatorKey).(Authenticator)
+	return authenticator, ok
+}
+
+// withTransport returns a copy of ctx carrying the transport of the client sending the request.
+func withTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	return context.WithValue(ctx, transportKey, transport)
+}
+
+// transportFromContext returns the transport stored in ctx, if any.
+func transportFromContext(ctx context.Context) (http.RoundTripper, bool) {
+	transport, ok := ctx.Value(transportKey).(http.RoundTripper)
+	return transport, ok && transport != nil
+}
@@ -0,0 +1,226 @@
+package webs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EnableCookies stores cookies set by responses in an in-memory jar and sends them with later requests,
+// which keeps login sessions alive. It has no effect when a jar has already been set with SetCookieJar.
+func (cb *ClientBuilder) EnableCookies() *ClientBuilder {
+	if cb.cookieJar == nil {
+		jar, _ := cookiejar.New(nil)
+		cb.cookieJar = jar
+	}
+	return cb
+}
+
+// SetCookieJar sets the jar used to store and send cookies, replacing the one created by EnableCookies.
+func (cb *ClientBuilder) SetCookieJar(jar http.CookieJar) *ClientBuilder {
+	cb.cookieJar = jar
+	return cb
+}
+
+// PersistCookies saves the cookies of the jar to the JSON file at path whenever they change, and loads them back when
+// the client is built, so sessions survive between runs. Cookies are enabled if no jar has been set. Session cookies
+// are persisted too, and the file is written with permissions restricted to the current user.
+func (cb *ClientBuilder) PersistCookies(path string) *ClientBuilder {
+	cb.cookieFile = path
+	return cb.EnableCookies()
+}
+
+// getCookieJar returns the cookie jar of the client, wrapped to persist its cookies when a file has been configured.
+func (cb *ClientBuilder) getCookieJar() (http.CookieJar, error) {
+	if cb.cookieJar == nil || cb.cookieFile == "" {
+		return cb.cookieJar, nil
+	}
+	jar := &persistentJar{CookieJar: cb.cookieJar, path: cb.cookieFile, logger: cb.getLogger(), cookies: make(map[string]storedCookie)}
+	if err := jar.load(); err != nil {
+		return nil, fmt.Errorf("failed to load cookies from %s: %w", cb.cookieFile, err)
+	}
+	return jar, nil
+}
+
+// Cookies parses and returns the cookies set by the Set-Cookie headers of the response.
+func (r *Response) Cookies() []*http.Cookie {
+	return (&http.Response{Header: r.headers}).Cookies()
+}
+
+// storedCookie is the JSON representation of a persisted cookie together with the URL that set it.
+type storedCookie struct {
+	URL      string        `json:"url"`
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Domain   string        `json:"domain,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Expires  *time.Time    `json:"expires,omitempty"`
+	Secure   bool          `json:"secure,omitempty"`
+	HttpOnly bool          `json:"httpOnly,omitempty"`
+	SameSite http.SameSite `json:"sameSite,omitempty"`
+}
+
+// expired reports whether the cookie has expired at now. Session cookies never expire.
+func (s storedCookie) expired(now time.Time) bool {
+	return s.Expires != nil && !s.Expires.After(now)
+}
+
+// cookie returns the http.Cookie replayed into the jar when the file is loaded.
+func (s storedCookie) cookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     s.Name,
+		Value:    s.Value,
+		Domain:   s.Domain,
+		Path:     s.Path,
+		Secure:   s.Secure,
+		HttpOnly: s.HttpOnly,
+		SameSite: s.SameSite,
+	}
+	if s.Expires != nil {
+		cookie.Expires = *s.Expires
+	}
+	return cookie
+}
+
+// persistentJar wraps a cookie jar and mirrors the cookies stored in it to a JSON file.
+// Jars do not expose the attributes of their cookies, so the wrapper keeps its own record of them.
+type persistentJar struct {
+	http.CookieJar
+	path    string
+	logger  *slog.Logger
+	mu      sync.Mutex
+	cookies map[string]storedCookie
+}
+
+// SetCookies stores the cookies in the wrapped jar and saves the updated set to the file.
+// A failure to save is logged rather than failing the request.
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	for _, cookie := range cookies {
+		stored := storedCookie{
+			URL:      u.String(),
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			SameSite: cookie.SameSite,
+		}
+		switch {
+		case cookie.MaxAge < 0:
+			expires := now
+			stored.Expires = &expires
+		case cookie.MaxAge > 0:
+			expires := now.Add(time.Duration(cookie.MaxAge) * time.Second)
+			stored.Expires = &expires
+		case !cookie.Expires.IsZero():
+			expires := cookie.Expires
+			stored.Expires = &expires
+		}
+
+		key := cookieKey(u, cookie)
+		if stored.expired(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		j.cookies[key] = stored
+	}
+
+	if err := j.save(now); err != nil {
+		j.logger.Warn("webs: failed to persist cookies", "path", j.path, "error", err)
+	}
+}
+
+// load replays the unexpired cookies of the file into the wrapped jar. A missing file is not an error.
+func (j *persistentJar) load() error {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stored []storedCookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, s := range stored {
+		if s.expired(now) {
+			continue
+		}
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return err
+		}
+		cookie := s.cookie()
+		j.CookieJar.SetCookies(u, []*http.Cookie{cookie})
+		j.cookies[cookieKey(u, cookie)] = s
+	}
+	return nil
+}
+
+// save writes the unexpired cookies to the file, replacing it atomically. It must be called with the lock held.
+func (j *persistentJar) save(now time.Time) error {
+	stored := make([]storedCookie, 0, len(j.cookies))
+	for key, cookie := range j.cookies {
+		if cookie.expired(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		stored = append(stored, cookie)
+	}
+	sort.Slice(stored, func(a, b int) bool {
+		if stored[a].URL != stored[b].URL {
+			return stored[a].URL < stored[b].URL
+		}
+		return stored[a].Name < stored[b].Name
+	})
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), j.path)
+}
+
+// cookieKey identifies a cookie the way a jar does, by its domain, path, and name.
+func cookieKey(u *url.URL, cookie *http.Cookie) string {
+	domain := cookie.Domain
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	return domain + ";" + cookie.Path + ";" + cookie.Name
+}
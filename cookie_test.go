@@ -0,0 +1,156 @@
+package webs
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newSessionServer starts a server that sets a session cookie on /login, clears it on /logout, and echoes it on /me.
+func newSessionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/", MaxAge: 3600})
+			http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/"})
+		case "/logout":
+			http.SetCookie(w, &http.Cookie{Name: "session", Path: "/", MaxAge: -1})
+		case "/me":
+			cookie, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(cookie.Value))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClientBuilder_EnableCookies verifies that cookies set by a response are sent with later requests.
+func TestClientBuilder_EnableCookies(t *testing.T) {
+	server := newSessionServer(t)
+
+	t.Run("disabledByDefault", func(t *testing.T) {
+		client := NewClientBuilder().Build()
+		_, _ = client.Get(server.URL+"/login", nil)
+		res, err := client.Get(server.URL+"/me", nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.StatusCode() != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", res.StatusCode())
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client := NewClientBuilder().EnableCookies().Build()
+		_, _ = client.Get(server.URL+"/login", nil)
+		res, err := client.Get(server.URL+"/me", nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "abc123" {
+			t.Errorf("expected abc123, got %s", res.String())
+		}
+	})
+}
+
+// TestClientBuilder_SetCookieJar verifies that a custom jar is used to store and send cookies.
+func TestClientBuilder_SetCookieJar(t *testing.T) {
+	server := newSessionServer(t)
+	jar, _ := cookiejar.New(nil)
+
+	client := NewClientBuilder().SetCookieJar(jar).EnableCookies().Build()
+	_, _ = client.Get(server.URL+"/login", nil)
+
+	res, _ := client.Get(server.URL+"/me", nil)
+	if res.String() != "abc123" {
+		t.Errorf("expected abc123, got %s", res.String())
+	}
+	u, _ := url.Parse(server.URL)
+	if cookies := jar.Cookies(u); len(cookies) != 2 {
+		t.Errorf("expected 2 cookies in the jar, got %d", len(cookies))
+	}
+}
+
+// TestClientBuilder_PersistCookies verifies that cookies are saved to a file and restored by a new client.
+func TestClientBuilder_PersistCookies(t *testing.T) {
+	server := newSessionServer(t)
+	path := filepath.Join(t.TempDir(), "cookies.json")
+
+	client := NewClientBuilder().PersistCookies(path).Build()
+	if _, err := client.Get(server.URL+"/login", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected cookie file, got %v", err)
+	}
+	if !strings.Contains(string(data), "abc123") {
+		t.Errorf("expected session cookie in file, got %s", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected permissions 0600, got %v", info.Mode().Perm())
+	}
+
+	restored := NewClientBuilder().PersistCookies(path).Build()
+	res, err := restored.Get(server.URL+"/me", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "abc123" {
+		t.Errorf("expected abc123, got %s", res.String())
+	}
+
+	if _, err := restored.Get(server.URL+"/logout", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "abc123") {
+		t.Errorf("expected expired session cookie to be removed, got %s", data)
+	}
+	if !strings.Contains(string(data), "theme") {
+		t.Errorf("expected theme cookie to be kept, got %s", data)
+	}
+}
+
+// TestClientBuilder_PersistCookiesInvalidFile verifies that a malformed cookie file is reported by every request.
+func TestClientBuilder_PersistCookiesInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	client := NewClientBuilder().PersistCookies(path).Build()
+	if _, err := client.Get("https://server.example", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+// TestResponse_Cookies verifies that the cookies set by a response are parsed from its headers.
+func TestResponse_Cookies(t *testing.T) {
+	headers := http.Header{}
+	headers.Add("Set-Cookie", "session=abc123; Path=/; HttpOnly")
+	headers.Add("Set-Cookie", "theme=dark")
+
+	cookies := NewResponse(http.StatusOK, headers, nil).Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("expected 2 cookies, got %d", len(cookies))
+	}
+	if cookies[0].Name != "session" || cookies[0].Value != "abc123" || !cookies[0].HttpOnly {
+		t.Errorf("expected HttpOnly session=abc123, got %v", cookies[0])
+	}
+	if cookies[1].Name != "theme" || cookies[1].Value != "dark" {
+		t.Errorf("expected theme=dark, got %v", cookies[1])
+	}
+}
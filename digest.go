@@ -0,0 +1,229 @@
+package webs
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestHashes maps the Digest algorithms of RFC 7616 to their hash functions, the "-sess" variants sharing them.
+var digestHashes = map[string]func() hash.Hash{
+	"MD5":     md5.New,
+	"SHA-256": sha256.New,
+}
+
+// DigestAuth is an Authenticator implementing HTTP Digest authentication as defined by RFC 7616, with the MD5 and
+// SHA-256 algorithms and qop=auth. The first request to a host is answered with a challenge and retried once with
+// credentials. The nonce is then cached per host so that later requests are authenticated up front.
+type DigestAuth struct {
+	username   string
+	password   string
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge
+	cnonce     func() string
+}
+
+// digestChallenge is a Digest challenge received from a host, along with the number of requests that used its nonce.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	count     uint32
+}
+
+// NewDigestAuth creates a DigestAuth authenticating with the username and password.
+func NewDigestAuth(username, password string) *DigestAuth {
+	return &DigestAuth{username: username, password: password, challenges: make(map[string]*digestChallenge)}
+}
+
+// Authenticate adds the Digest credentials to the request when a challenge of its host has been cached.
+// Requests to a host that has not challenged the client yet are sent without credentials.
+func (d *DigestAuth) Authenticate(request *http.Request) error {
+	d.mu.Lock()
+	challenge, ok := d.challenges[request.URL.Host]
+	if !ok {
+		d.mu.Unlock()
+		return nil
+	}
+	challenge.count++
+	current := *challenge
+	d.mu.Unlock()
+
+	request.Header.Set("Authorization", d.authorization(request, &current))
+	return nil
+}
+
+// Challenge caches the Digest challenge of the response for the host of the request. It reports whether the request
+// should be retried, which is not the case when the rejected credentials were computed from the same, still valid,
+// nonce, as they would be rejected again.
+func (d *DigestAuth) Challenge(request *http.Request, response *http.Response) bool {
+	challenge, stale, ok := parseDigestChallenge(response.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous, cached := d.challenges[request.URL.Host]
+	d.challenges[request.URL.Host] = challenge
+	if request.Header.Get("Authorization") == "" {
+		return true
+	}
+	return stale || !cached || previous.nonce != challenge.nonce
+}
+
+// authorization computes the Authorization header value of the request for the challenge.
+func (d *DigestAuth) authorization(request *http.Request, challenge *digestChallenge) string {
+	algorithm := strings.ToUpper(challenge.algorithm)
+	newHash := digestHashes[strings.TrimSuffix(algorithm, "-SESS")]
+	h := func(data string) string {
+		sum := newHash()
+		sum.Write([]byte(data))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	uri := request.URL.RequestURI()
+	cnonce := d.getCnonce()
+	nc := fmt.Sprintf("%08x", challenge.count)
+
+	ha1 := h(d.username + ":" + challenge.realm + ":" + d.password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(ha1 + ":" + challenge.nonce + ":" + cnonce)
+	}
+	ha2 := h(request.Method + ":" + uri)
+
+	var response string
+	if challenge.qop == "" {
+		response = h(ha1 + ":" + challenge.nonce + ":" + ha2)
+	} else {
+		response = h(strings.Join([]string{ha1, challenge.nonce, nc, cnonce, challenge.qop, ha2}, ":"))
+	}
+
+	params := []string{
+		"username=" + quoteDigest(d.username),
+		"realm=" + quoteDigest(challenge.realm),
+		"uri=" + quoteDigest(uri),
+		"algorithm=" + challenge.algorithm,
+		"nonce=" + quoteDigest(challenge.nonce),
+	}
+	if challenge.qop != "" {
+		params = append(params, "nc="+nc, "cnonce="+quoteDigest(cnonce), "qop="+challenge.qop)
+	}
+	params = append(params, "response="+quoteDigest(response))
+	if challenge.opaque != "" {
+		params = append(params, "opaque="+quoteDigest(challenge.opaque))
+	}
+	return "Digest " + strings.Join(params, ", ")
+}
+
+// getCnonce returns a random client nonce, which tests can replace.
+func (d *DigestAuth) getCnonce() string {
+	if d.cnonce != nil {
+		return d.cnonce()
+	}
+	buffer := make([]byte, 16)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+// parseDigestChallenge returns the strongest supported Digest challenge offered by the WWW-Authenticate headers and
+// whether it marks the previous nonce as stale. Challenges without qop=auth are accepted for compatibility with
+// RFC 2069 servers, while challenges only offering qop=auth-int are not supported.
+func parseDigestChallenge(headers []string) (*digestChallenge, bool, bool) {
+	var best *digestChallenge
+	var bestStale bool
+	for _, header := range headers {
+		for _, challenge := range splitChallenges(header) {
+			scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+			if !strings.EqualFold(scheme, "Digest") {
+				continue
+			}
+			params := parseAuthParams(rest)
+
+			parsed := &digestChallenge{
+				realm:     params["realm"],
+				nonce:     params["nonce"],
+				opaque:    params["opaque"],
+				algorithm: params["algorithm"],
+			}
+			if parsed.algorithm == "" {
+				parsed.algorithm = "MD5"
+			}
+			if _, ok := digestHashes[strings.TrimSuffix(strings.ToUpper(parsed.algorithm), "-SESS")]; !ok || parsed.nonce == "" {
+				continue
+			}
+			if qop, ok := params["qop"]; ok {
+				for _, option := range strings.Split(qop, ",") {
+					if strings.TrimSpace(option) == "auth" {
+						parsed.qop = "auth"
+					}
+				}
+				if parsed.qop == "" {
+					continue
+				}
+			}
+
+			if best == nil || digestStrength(parsed.algorithm) > digestStrength(best.algorithm) {
+				best, bestStale = parsed, strings.EqualFold(params["stale"], "true")
+			}
+		}
+	}
+	return best, bestStale, best != nil
+}
+
+// digestStrength ranks the supported algorithms so that SHA-256 is preferred over MD5.
+func digestStrength(algorithm string) int {
+	if strings.HasPrefix(strings.ToUpper(algorithm), "SHA-256") {
+		return 1
+	}
+	return 0
+}
+
+// splitChallenges splits a WWW-Authenticate header that may contain several challenges, such as
+// `Basic realm="api", Digest realm="api", nonce="abc"`, into one string per challenge.
+func splitChallenges(header string) []string {
+	var challenges []string
+	for _, part := range splitOutside(header, ',') {
+		trimmed := strings.TrimSpace(part)
+		name, _, _ := strings.Cut(trimmed, "=")
+		if len(challenges) > 0 && !strings.Contains(strings.TrimSpace(name), " ") {
+			challenges[len(challenges)-1] += "," + part
+			continue
+		}
+		challenges = append(challenges, trimmed)
+	}
+	return challenges
+}
+
+// parseAuthParams parses the comma-separated auth-params of a challenge, unquoting quoted values.
+// Parameter names are lowercased.
+func parseAuthParams(params string) map[string]string {
+	values := make(map[string]string)
+	for _, param := range splitOutside(params, ',') {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+			value = strings.ReplaceAll(value, `\\`, `\`)
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return values
+}
+
+// quoteDigest returns value as a quoted string, escaping quotes and backslashes.
+func quoteDigest(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
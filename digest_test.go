@@ -0,0 +1,174 @@
+package webs
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestDigestAuth_RFC7616 verifies the Authorization header against the examples of RFC 7616, section 3.9.1.
+func TestDigestAuth_RFC7616(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		expected  string
+	}{
+		{"MD5", "8ca523f5e9506fed4657c9700eebdbec"},
+		{"SHA-256", "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			digest := NewDigestAuth("Mufasa", "Circle of Life")
+			digest.cnonce = func() string { return "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ" }
+
+			header := fmt.Sprintf(`Digest realm="http-auth@example.org", qop="auth, auth-int", algorithm=%s, `+
+				`nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`, tt.algorithm)
+			request, _ := http.NewRequest(http.MethodGet, "http://www.example.org/dir/index.html", nil)
+			response := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{"Www-Authenticate": {header}}}
+
+			if !digest.Challenge(request, response) {
+				t.Fatal("expected the request to be retried")
+			}
+			if err := digest.Authenticate(request); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			expected := `Digest username="Mufasa", realm="http-auth@example.org", uri="/dir/index.html", ` +
+				`algorithm=` + tt.algorithm + `, nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", nc=00000001, ` +
+				`cnonce="f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ", qop=auth, response="` + tt.expected + `", ` +
+				`opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`
+			if authorization := request.Header.Get("Authorization"); authorization != expected {
+				t.Errorf("expected %s, got %s", expected, authorization)
+			}
+		})
+	}
+}
+
+// TestParseDigestChallenge verifies that the strongest supported Digest challenge is selected.
+func TestParseDigestChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		headers   []string
+		algorithm string
+		stale     bool
+		ok        bool
+	}{
+		{"sha256Preferred", []string{`Basic realm="api", Digest realm="api", nonce="a", algorithm=MD5, qop="auth"`, `Digest realm="api", nonce="b", algorithm=SHA-256, qop="auth"`}, "SHA-256", false, true},
+		{"defaultMD5", []string{`Digest realm="api", nonce="a"`}, "MD5", false, true},
+		{"stale", []string{`Digest realm="api", nonce="a", qop="auth", stale=TRUE`}, "MD5", true, true},
+		{"authIntOnly", []string{`Digest realm="api", nonce="a", qop="auth-int"`}, "", false, false},
+		{"unsupportedAlgorithm", []string{`Digest realm="api", nonce="a", algorithm=SHA-512-256`}, "", false, false},
+		{"basicOnly", []string{`Basic realm="api"`}, "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge, stale, ok := parseDigestChallenge(tt.headers)
+			if ok != tt.ok {
+				t.Fatalf("expected ok to be %v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if challenge.algorithm != tt.algorithm || stale != tt.stale {
+				t.Errorf("expected %s with stale %v, got %s with stale %v", tt.algorithm, tt.stale, challenge.algorithm, stale)
+			}
+		})
+	}
+}
+
+// digestServer is a server protected by MD5 Digest authentication that records the requests it receives.
+type digestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	nonce    string
+	requests []string
+}
+
+// newDigestServer starts a server accepting the user "admin" with the password "secret".
+func newDigestServer(t *testing.T) *digestServer {
+	t.Helper()
+	ds := &digestServer{nonce: "nonce-1"}
+	ds.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ds.mu.Lock()
+		defer ds.mu.Unlock()
+
+		authorization := r.Header.Get("Authorization")
+		params := parseAuthParams(strings.TrimPrefix(authorization, "Digest "))
+		ds.requests = append(ds.requests, params["nc"])
+
+		stale := params["nonce"] != "" && params["nonce"] != ds.nonce
+		if authorization == "" || stale || params["response"] != ds.expected(r, params) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="appliance", nonce="%s", qop="auth", stale=%v`, ds.nonce, stale))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("welcome"))
+	}))
+	t.Cleanup(ds.Close)
+	return ds
+}
+
+// expected computes the response expected from the client for the request.
+func (ds *digestServer) expected(r *http.Request, params map[string]string) string {
+	h := func(data string) string {
+		sum := md5.Sum([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+	ha1 := h("admin:appliance:secret")
+	ha2 := h(r.Method + ":" + r.URL.RequestURI())
+	return h(strings.Join([]string{ha1, ds.nonce, params["nc"], params["cnonce"], "auth", ha2}, ":"))
+}
+
+// TestDigestAuth_Client verifies the challenge round trip, nonce caching per host, and stale nonces.
+func TestDigestAuth_Client(t *testing.T) {
+	server := newDigestServer(t)
+	client := NewClientBuilder().SetAuthenticator(NewDigestAuth("admin", "secret")).Build()
+
+	res, err := client.Post(server.URL+"/config?section=network", nil, "mode=dhcp")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "welcome" {
+		t.Errorf("expected welcome, got %d %s", res.StatusCode(), res.String())
+	}
+
+	_, _ = client.Get(server.URL+"/status", nil)
+
+	server.mu.Lock()
+	server.nonce = "nonce-2"
+	server.mu.Unlock()
+	res, _ = client.Get(server.URL+"/status", nil)
+	if res.String() != "welcome" {
+		t.Errorf("expected welcome after a stale nonce, got %d %s", res.StatusCode(), res.String())
+	}
+
+	expected := []string{"", "00000001", "00000002", "00000003", "00000001"}
+	if strings.Join(server.requests, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected nonce counts %v, got %v", expected, server.requests)
+	}
+}
+
+// TestDigestAuth_WrongPassword verifies that rejected credentials are not retried with the same nonce.
+func TestDigestAuth_WrongPassword(t *testing.T) {
+	server := newDigestServer(t)
+	client := NewClientBuilder().SetAuthenticator(NewDigestAuth("admin", "wrong")).Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", res.StatusCode())
+	}
+
+	_, _ = client.Get(server.URL, nil)
+	if len(server.requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", len(server.requests))
+	}
+}
@@ -0,0 +1,143 @@
+package webs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// maxErrorBodySize caps how many bytes of the response body are kept in an HTTPError.
+	maxErrorBodySize = 4 << 10
+
+	// maxDecodeSnippetSize caps how many bytes of the response body are kept in a DecodeError.
+	maxDecodeSnippetSize = 256
+)
+
+var (
+	// ErrClientError is matched by an HTTPError carrying a 4xx status code.
+	ErrClientError = errors.New("client error")
+
+	// ErrServerError is matched by an HTTPError carrying a 5xx status code.
+	ErrServerError = errors.New("server error")
+
+	// ErrRateLimited is matched by an HTTPError carrying a 429 Too Many Requests status code.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrResponseTooLarge is matched by a ResponseTooLargeError.
+	ErrResponseTooLarge = errors.New("response body too large")
+
+	// ErrUnsupportedMediaType is returned when no codec is registered for the content type of a body.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// ErrTooManyRedirects is returned when a request is redirected more times than allowed by SetMaxRedirects.
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// HTTPError is returned when HTTP errors are enabled on the ClientBuilder and a request ends with a non-2xx status code.
+// Use errors.As to retrieve it, or errors.Is with ErrClientError, ErrServerError, or ErrRateLimited to categorise it.
+type HTTPError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Header     http.Header
+
+	// Body holds the beginning of the response body, truncated to a few kilobytes.
+	Body []byte
+}
+
+// newHTTPError creates an HTTPError describing the response received for the given request.
+func newHTTPError(request *http.Request, response *Response) *HTTPError {
+	return newStatusError(request.Method, request.URL.Redacted(), response)
+}
+
+// newStatusError creates an HTTPError describing the response received for a request with the given method and URL.
+func newStatusError(method, url string, response *Response) *HTTPError {
+	body := response.body
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	return &HTTPError{
+		Method:     method,
+		URL:        url,
+		StatusCode: response.statusCode,
+		Status:     response.status,
+		Header:     response.headers,
+		Body:       body,
+	}
+}
+
+// Error returns a description of the failed request including its method, URL, and status.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %s", e.Method, e.URL, e.Status)
+}
+
+// Is reports whether the error belongs to the category described by target.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrClientError:
+		return e.StatusCode >= 400 && e.StatusCode < 500
+	case ErrServerError:
+		return e.StatusCode >= 500 && e.StatusCode < 600
+	default:
+		return false
+	}
+}
+
+// ResponseTooLargeError is returned when a response body exceeds the configured maximum size.
+// Size holds the declared Content-Length when the body was rejected up front, or the number of bytes read otherwise.
+type ResponseTooLargeError struct {
+	Limit int64
+	Size  int64
+}
+
+// Error returns a description of the oversized response including the limit and the size observed.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %d bytes exceeds the limit of %d", ErrResponseTooLarge, e.Size, e.Limit)
+}
+
+// Unwrap returns ErrResponseTooLarge so the error can be matched with errors.Is.
+func (e *ResponseTooLargeError) Unwrap() error {
+	return ErrResponseTooLarge
+}
+
+// DecodeError is returned by the typed helpers when a response body cannot be decoded into the requested type.
+type DecodeError struct {
+	StatusCode int
+
+	// Snippet holds the beginning of the response body that failed to decode.
+	Snippet string
+
+	Err error
+}
+
+// newDecodeError wraps err with the status code and the beginning of the body of the response.
+func newDecodeError(response *Response, err error) *DecodeError {
+	snippet := response.Bytes()
+	if len(snippet) > maxDecodeSnippetSize {
+		snippet = snippet[:maxDecodeSnippetSize]
+	}
+	return &DecodeError{
+		StatusCode: response.StatusCode(),
+		Snippet:    string(snippet),
+		Err:        err,
+	}
+}
+
+// Error returns a description of the decoding failure including the status code and the body snippet.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode response with status %d: %v (body: %q)", e.StatusCode, e.Err, e.Snippet)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// isSuccess reports whether the status code is in the 2xx range.
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
@@ -0,0 +1,167 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"github.com/h2non/gock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHTTPError_Disabled verifies that non-2xx responses do not produce an error unless HTTP errors are enabled.
+func TestHTTPError_Disabled(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/missing").
+		Reply(404)
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	res, err := client.Get("https://server.com/missing", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != 404 {
+		t.Errorf("expected status code 404, got %d", res.StatusCode())
+	}
+}
+
+// TestHTTPError_Enabled verifies that a non-2xx response is returned as an *HTTPError describing the request and response.
+func TestHTTPError_Enabled(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Delete("/items/1").
+		Reply(409).
+		SetHeader("X-Trace", "trace-1").
+		BodyString(strings.Repeat("x", maxErrorBodySize+10))
+
+	client := NewClientBuilder().EnableHTTPErrors(true).Build()
+	gock.InterceptClient(client.client)
+
+	res, err := client.Delete("https://server.com/items/1", nil)
+	if res == nil {
+		t.Fatal("expected response, got nil")
+	}
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected *HTTPError, got %v", err)
+	}
+	if httpErr.StatusCode != 409 {
+		t.Errorf("expected status code 409, got %d", httpErr.StatusCode)
+	}
+	if httpErr.Method != http.MethodDelete {
+		t.Errorf("expected method DELETE, got %s", httpErr.Method)
+	}
+	if httpErr.URL != "https://server.com/items/1" {
+		t.Errorf("expected URL https://server.com/items/1, got %s", httpErr.URL)
+	}
+	if httpErr.Header.Get("X-Trace") != "trace-1" {
+		t.Errorf("expected X-Trace header trace-1, got %s", httpErr.Header.Get("X-Trace"))
+	}
+	if len(httpErr.Body) != maxErrorBodySize {
+		t.Errorf("expected body to be truncated to %d bytes, got %d", maxErrorBodySize, len(httpErr.Body))
+	}
+}
+
+// TestHTTPError_Categories verifies that HTTPError matches the sentinel categories through errors.Is.
+func TestHTTPError_Categories(t *testing.T) {
+	tests := []struct {
+		statusCode  int
+		clientError bool
+		serverError bool
+		rateLimited bool
+	}{
+		{http.StatusBadRequest, true, false, false},
+		{http.StatusTooManyRequests, true, false, true},
+		{http.StatusInternalServerError, false, true, false},
+		{http.StatusMovedPermanently, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := error(&HTTPError{StatusCode: tt.statusCode})
+			if got := errors.Is(err, ErrClientError); got != tt.clientError {
+				t.Errorf("expected ErrClientError match to be %v, got %v", tt.clientError, got)
+			}
+			if got := errors.Is(err, ErrServerError); got != tt.serverError {
+				t.Errorf("expected ErrServerError match to be %v, got %v", tt.serverError, got)
+			}
+			if got := errors.Is(err, ErrRateLimited); got != tt.rateLimited {
+				t.Errorf("expected ErrRateLimited match to be %v, got %v", tt.rateLimited, got)
+			}
+		})
+	}
+}
+
+// TestResponseTooLarge_DeclaredLength verifies that a body declaring a Content-Length above the limit is rejected before reading.
+func TestResponseTooLarge_DeclaredLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "2048")
+		_, _ = w.Write(make([]byte, 2048))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().SetMaxResponseBodySize(1024).Build()
+	_, err := client.Get(server.URL, nil)
+
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected *ResponseTooLargeError, got %v", err)
+	}
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if tooLarge.Size != 2048 || tooLarge.Limit != 1024 {
+		t.Errorf("expected size 2048 and limit 1024, got size %d and limit %d", tooLarge.Size, tooLarge.Limit)
+	}
+}
+
+// TestResponseTooLarge_ChunkedBody verifies that a body without Content-Length is rejected once it exceeds the limit.
+func TestResponseTooLarge_ChunkedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 4; i++ {
+			_, _ = w.Write(make([]byte, 512))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().SetMaxResponseBodySize(1024).Build()
+	_, err := client.Get(server.URL, nil)
+
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected *ResponseTooLargeError, got %v", err)
+	}
+	if tooLarge.Size != 1025 {
+		t.Errorf("expected partial size 1025, got %d", tooLarge.Size)
+	}
+}
+
+// TestResponseTooLarge_PerRequestOverride verifies that the limit can be raised or lowered for a single request.
+func TestResponseTooLarge_PerRequestOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, 2048))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().SetMaxResponseBodySize(1024).Build()
+
+	res, err := client.GetContext(WithMaxResponseBodySize(context.Background(), 4096), server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(res.Bytes()) != 2048 {
+		t.Errorf("expected 2048 bytes, got %d", len(res.Bytes()))
+	}
+
+	_, err = client.GetContext(WithMaxResponseBodySize(context.Background(), 16), server.URL, nil)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
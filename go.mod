@@ -2,7 +2,6 @@ module github.com/madalinpopa/webs
 
 go 1.23.2
 
-require (
-	github.com/h2non/gock v1.2.0 // indirect
-	github.com/h2non/parth v0.0.0-20190131123155-b4df798d6542 // indirect
-)
+require github.com/h2non/gock v1.2.0
+
+require github.com/h2non/parth v0.0.0-20190131123155-b4df798d6542 // indirect
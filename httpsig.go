@@ -0,0 +1,696 @@
+package webs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSignatureLabel is the label of the signatures created by a MessageSigner without one.
+	defaultSignatureLabel = "sig1"
+
+	// AlgorithmHMACSHA256 identifies HMAC using SHA-256 in RFC 9421.
+	AlgorithmHMACSHA256 = "hmac-sha256"
+
+	// AlgorithmEd25519 identifies EdDSA using curve edwards25519 in RFC 9421.
+	AlgorithmEd25519 = "ed25519"
+
+	// AlgorithmECDSAP256SHA256 identifies ECDSA using curve P-256 and SHA-256 in RFC 9421.
+	AlgorithmECDSAP256SHA256 = "ecdsa-p256-sha256"
+)
+
+var (
+	// ErrInvalidSignature is returned when a message signature is missing, malformed, expired, or does not verify.
+	ErrInvalidSignature = errors.New("invalid HTTP message signature")
+
+	// ErrContentDigestMismatch is returned when a body does not match its Content-Digest header.
+	ErrContentDigestMismatch = errors.New("content digest mismatch")
+)
+
+// MessageSigningKey signs the signature base of an HTTP message.
+type MessageSigningKey interface {
+	Algorithm() string
+	SignMessage(base []byte) ([]byte, error)
+}
+
+// MessageVerifyingKey verifies the signature of the signature base of an HTTP message.
+type MessageVerifyingKey interface {
+	Algorithm() string
+	VerifyMessage(base, signature []byte) error
+}
+
+// HMACKey is a shared secret used to both sign and verify messages with HMAC-SHA256.
+type HMACKey struct {
+	secret []byte
+}
+
+// NewHMACSHA256Key creates an HMACKey from a shared secret.
+func NewHMACSHA256Key(secret []byte) *HMACKey {
+	return &HMACKey{secret: secret}
+}
+
+// Algorithm returns "hmac-sha256".
+func (k *HMACKey) Algorithm() string {
+	return AlgorithmHMACSHA256
+}
+
+// SignMessage returns the HMAC-SHA256 of the signature base.
+func (k *HMACKey) SignMessage(base []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(base)
+	return mac.Sum(nil), nil
+}
+
+// VerifyMessage compares the signature with the HMAC-SHA256 of the signature base in constant time.
+func (k *HMACKey) VerifyMessage(base, signature []byte) error {
+	expected, _ := k.SignMessage(base)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ed25519Key signs or verifies messages with an Ed25519 key.
+type ed25519Key struct {
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// NewEd25519SigningKey creates a MessageSigningKey from an Ed25519 private key.
+func NewEd25519SigningKey(key ed25519.PrivateKey) MessageSigningKey {
+	return &ed25519Key{private: key}
+}
+
+// NewEd25519VerifyingKey creates a MessageVerifyingKey from an Ed25519 public key.
+func NewEd25519VerifyingKey(key ed25519.PublicKey) MessageVerifyingKey {
+	return &ed25519Key{public: key}
+}
+
+// Algorithm returns "ed25519".
+func (k *ed25519Key) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// SignMessage returns the Ed25519 signature of the signature base.
+func (k *ed25519Key) SignMessage(base []byte) ([]byte, error) {
+	return ed25519.Sign(k.private, base), nil
+}
+
+// VerifyMessage verifies the Ed25519 signature of the signature base.
+func (k *ed25519Key) VerifyMessage(base, signature []byte) error {
+	if !ed25519.Verify(k.public, base, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ecdsaKey signs or verifies messages with an ECDSA P-256 key. Signatures are the concatenation of r and s.
+type ecdsaKey struct {
+	private *ecdsa.PrivateKey
+	public  *ecdsa.PublicKey
+}
+
+// NewECDSAP256SigningKey creates a MessageSigningKey from an ECDSA private key on curve P-256.
+func NewECDSAP256SigningKey(key *ecdsa.PrivateKey) MessageSigningKey {
+	return &ecdsaKey{private: key}
+}
+
+// NewECDSAP256VerifyingKey creates a MessageVerifyingKey from an ECDSA public key on curve P-256.
+func NewECDSAP256VerifyingKey(key *ecdsa.PublicKey) MessageVerifyingKey {
+	return &ecdsaKey{public: key}
+}
+
+// Algorithm returns "ecdsa-p256-sha256".
+func (k *ecdsaKey) Algorithm() string {
+	return AlgorithmECDSAP256SHA256
+}
+
+// SignMessage returns the ECDSA signature of the SHA-256 hash of the signature base.
+func (k *ecdsaKey) SignMessage(base []byte) ([]byte, error) {
+	if k.private.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("ECDSA key must use curve P-256, got %s", k.private.Curve.Params().Name)
+	}
+	digest := sha256.Sum256(base)
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signature, nil
+}
+
+// VerifyMessage verifies the ECDSA signature of the SHA-256 hash of the signature base.
+func (k *ecdsaKey) VerifyMessage(base, signature []byte) error {
+	if len(signature) != 64 {
+		return ErrInvalidSignature
+	}
+	digest := sha256.Sum256(base)
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(k.public, digest[:], r, s) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MessageSigner is an Authenticator that signs requests following RFC 9421, HTTP Message Signatures. It adds the
+// Signature-Input and Signature headers, and the Content-Digest header when it is covered but missing.
+type MessageSigner struct {
+	// Label names the signature in the Signature-Input and Signature headers. Defaults to "sig1".
+	Label string
+
+	// KeyID identifies the key to the verifier.
+	KeyID string
+
+	// Key signs the signature base.
+	Key MessageSigningKey
+
+	// Components lists the covered components: derived components such as "@method", "@target-uri", "@authority",
+	// "@path", and "@query", or header names such as "content-digest" and "date".
+	Components []string
+
+	// Tag is an optional application-specific tag added to the signature parameters.
+	Tag string
+
+	// Expires sets the validity of signatures. Zero adds no expiry.
+	Expires time.Duration
+
+	now func() time.Time
+}
+
+// NewMessageSigner creates a MessageSigner covering the given components, "@method" and "@target-uri" if none are given.
+func NewMessageSigner(keyID string, key MessageSigningKey, components ...string) *MessageSigner {
+	if len(components) == 0 {
+		components = []string{"@method", "@target-uri"}
+	}
+	return &MessageSigner{KeyID: keyID, Key: key, Components: components}
+}
+
+// Authenticate signs the request. It is equivalent to Sign. Use ChainAuthenticators to sign requests that also carry
+// a token, placing the signer last so that the Authorization header can be covered.
+func (s *MessageSigner) Authenticate(request *http.Request) error {
+	return s.Sign(request)
+}
+
+// Sign adds the Signature-Input and Signature headers to the request, computing its Content-Digest first when the
+// digest is covered and missing.
+func (s *MessageSigner) Sign(request *http.Request) error {
+	components := make([]string, len(s.Components))
+	for i, component := range s.Components {
+		components[i] = strings.ToLower(component)
+		if components[i] == "content-digest" && request.Header.Get("Content-Digest") == "" {
+			if err := SetContentDigest(request); err != nil {
+				return err
+			}
+		}
+	}
+
+	params := s.signatureParams(components)
+	base, err := signatureBase(components, params, requestComponent(request))
+	if err != nil {
+		return err
+	}
+	signature, err := s.Key.SignMessage(base)
+	if err != nil {
+		return err
+	}
+
+	label := s.Label
+	if label == "" {
+		label = defaultSignatureLabel
+	}
+	request.Header.Set("Signature-Input", label+"="+params)
+	request.Header.Set("Signature", label+"=:"+base64.StdEncoding.EncodeToString(signature)+":")
+	return nil
+}
+
+// signatureParams serializes the covered components and the signature parameters.
+func (s *MessageSigner) signatureParams(components []string) string {
+	var params strings.Builder
+	params.WriteString("(")
+	for i, component := range components {
+		if i > 0 {
+			params.WriteString(" ")
+		}
+		params.WriteString(strconv.Quote(component))
+	}
+	params.WriteString(")")
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	params.WriteString(";created=" + strconv.FormatInt(now.Unix(), 10))
+	if s.Expires > 0 {
+		params.WriteString(";expires=" + strconv.FormatInt(now.Add(s.Expires).Unix(), 10))
+	}
+	if s.KeyID != "" {
+		params.WriteString(";keyid=" + strconv.Quote(s.KeyID))
+	}
+	if s.Tag != "" {
+		params.WriteString(";tag=" + strconv.Quote(s.Tag))
+	}
+	return params.String()
+}
+
+// MessageVerifier verifies signatures created following RFC 9421, HTTP Message Signatures.
+type MessageVerifier struct {
+	// Keys maps key IDs to the keys verifying their signatures.
+	Keys map[string]MessageVerifyingKey
+
+	// Label selects the signature to verify. When empty, the message is valid if any of its signatures verifies.
+	Label string
+
+	// RequiredComponents lists components that a signature must cover to be accepted.
+	RequiredComponents []string
+
+	// MaxAge rejects signatures created longer ago. Zero accepts signatures of any age.
+	MaxAge time.Duration
+
+	now func() time.Time
+}
+
+// NewMessageVerifier creates a MessageVerifier accepting signatures made with the key identified by keyID.
+func NewMessageVerifier(keyID string, key MessageVerifyingKey) *MessageVerifier {
+	return &MessageVerifier{Keys: map[string]MessageVerifyingKey{keyID: key}}
+}
+
+// VerifyRequest verifies the signature of a request, such as a webhook received by a server. When the signature
+// covers Content-Digest, the digest is checked against the body, which is buffered so that it can still be read.
+func (v *MessageVerifier) VerifyRequest(request *http.Request) error {
+	return v.verify(request.Header, requestComponent(request), func(h hash.Hash) error {
+		if err := bufferRequestBody(request); err != nil {
+			return err
+		}
+		return hashRequestBody(request, h)
+	})
+}
+
+// bufferRequestBody reads a body that cannot be replayed into memory and makes it replayable through GetBody.
+func bufferRequestBody(request *http.Request) error {
+	if request.Body == nil || request.Body == http.NoBody || request.GetBody != nil {
+		return nil
+	}
+	data, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	if err != nil {
+		return err
+	}
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	request.Body, _ = request.GetBody()
+	return nil
+}
+
+// VerifySignature verifies the signature of the response with the verifier. The signature may cover the "@status"
+// component and response headers. When it covers Content-Digest, the digest is checked against the body.
+func (r *Response) VerifySignature(verifier *MessageVerifier) error {
+	return verifier.verify(r.headers, responseComponent(r), func(h hash.Hash) error {
+		h.Write(r.body)
+		return nil
+	})
+}
+
+// verify checks the signatures of a message, returning nil as soon as one of the selected signatures verifies.
+func (v *MessageVerifier) verify(headers http.Header, component componentFunc, hashBody func(hash.Hash) error) error {
+	inputs, err := parseDictionary(strings.Join(headers.Values("Signature-Input"), ", "))
+	if err != nil {
+		return fmt.Errorf("%w: malformed Signature-Input: %w", ErrInvalidSignature, err)
+	}
+	signatures, err := parseDictionary(strings.Join(headers.Values("Signature"), ", "))
+	if err != nil {
+		return fmt.Errorf("%w: malformed Signature: %w", ErrInvalidSignature, err)
+	}
+
+	var errs []error
+	for _, input := range inputs {
+		if v.Label != "" && input.key != v.Label {
+			continue
+		}
+		err := v.verifySignature(input, signatures, component)
+		if err == nil {
+			return v.verifyDigest(input.value, headers, hashBody)
+		}
+		errs = append(errs, fmt.Errorf("signature %q: %w", input.key, err))
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("%w: no signature found", ErrInvalidSignature)
+	}
+	return errors.Join(errs...)
+}
+
+// verifySignature verifies a single signature identified by its Signature-Input member.
+func (v *MessageVerifier) verifySignature(input dictionaryMember, signatures []dictionaryMember, component componentFunc) error {
+	var encoded string
+	for _, signature := range signatures {
+		if signature.key == input.key {
+			encoded = signature.value
+		}
+	}
+	if len(encoded) < 2 || encoded[0] != ':' || encoded[len(encoded)-1] != ':' {
+		return fmt.Errorf("%w: missing signature value", ErrInvalidSignature)
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded[1 : len(encoded)-1])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	components, params, err := parseSignatureParams(input.value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	for _, required := range v.RequiredComponents {
+		if !slices.Contains(components, strings.ToLower(required)) {
+			return fmt.Errorf("%w: required component %q is not covered", ErrInvalidSignature, required)
+		}
+	}
+
+	key, err := v.key(params)
+	if err != nil {
+		return err
+	}
+	if err := v.checkTimes(params); err != nil {
+		return err
+	}
+
+	base, err := signatureBase(components, input.value, component)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if err := key.VerifyMessage(base, signature); err != nil {
+		return fmt.Errorf("%w: signature does not match", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// key returns the key identified by the keyid parameter, or the only key of the verifier when there is no keyid.
+func (v *MessageVerifier) key(params map[string]string) (MessageVerifyingKey, error) {
+	keyID, ok := params["keyid"]
+	var key MessageVerifyingKey
+	if ok {
+		key = v.Keys[unquote(keyID)]
+	} else if len(v.Keys) == 1 {
+		for _, only := range v.Keys {
+			key = only
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w: unknown key %s", ErrInvalidSignature, keyID)
+	}
+	if alg, ok := params["alg"]; ok && unquote(alg) != key.Algorithm() {
+		return nil, fmt.Errorf("%w: algorithm %s does not match the key", ErrInvalidSignature, alg)
+	}
+	return key, nil
+}
+
+// checkTimes rejects expired signatures and signatures older than the maximum age of the verifier.
+func (v *MessageVerifier) checkTimes(params map[string]string) error {
+	now := time.Now()
+	if v.now != nil {
+		now = v.now()
+	}
+	if expires, ok := params["expires"]; ok {
+		seconds, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || !now.Before(time.Unix(seconds, 0)) {
+			return fmt.Errorf("%w: signature expired", ErrInvalidSignature)
+		}
+	}
+	if v.MaxAge > 0 {
+		created, ok := params["created"]
+		seconds, err := strconv.ParseInt(created, 10, 64)
+		if !ok || err != nil || now.Sub(time.Unix(seconds, 0)) > v.MaxAge {
+			return fmt.Errorf("%w: signature is older than %s", ErrInvalidSignature, v.MaxAge)
+		}
+	}
+	return nil
+}
+
+// verifyDigest checks the body against the Content-Digest header when the verified signature covers it.
+func (v *MessageVerifier) verifyDigest(signatureParams string, headers http.Header, hashBody func(hash.Hash) error) error {
+	components, _, _ := parseSignatureParams(signatureParams)
+	if !slices.Contains(components, "content-digest") {
+		return nil
+	}
+	return verifyContentDigest(headers.Get("Content-Digest"), hashBody)
+}
+
+// SetContentDigest sets the Content-Digest header of the request to the SHA-256 digest of its body, as defined by
+// RFC 9530. The body must be replayable through GetBody.
+func SetContentDigest(request *http.Request) error {
+	h := sha256.New()
+	if err := hashRequestBody(request, h); err != nil {
+		return fmt.Errorf("failed to compute content digest: %w", err)
+	}
+	request.Header.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(h.Sum(nil))+":")
+	return nil
+}
+
+// digestAlgorithms are the Content-Digest algorithms that can be verified.
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// verifyContentDigest checks every supported digest of the Content-Digest header against the body.
+func verifyContentDigest(header string, hashBody func(hash.Hash) error) error {
+	members, err := parseDictionary(header)
+	if err != nil {
+		return fmt.Errorf("%w: malformed Content-Digest: %w", ErrContentDigestMismatch, err)
+	}
+
+	verified := false
+	for _, member := range members {
+		newHash, ok := digestAlgorithms[member.key]
+		if !ok {
+			continue
+		}
+		h := newHash()
+		if err := hashBody(h); err != nil {
+			return err
+		}
+		expected := ":" + base64.StdEncoding.EncodeToString(h.Sum(nil)) + ":"
+		if member.value != expected {
+			return fmt.Errorf("%w: %s", ErrContentDigestMismatch, member.key)
+		}
+		verified = true
+	}
+	if !verified {
+		return fmt.Errorf("%w: no supported digest", ErrContentDigestMismatch)
+	}
+	return nil
+}
+
+// componentFunc returns the value of a covered component of a message.
+type componentFunc func(name string) (string, error)
+
+// requestComponent resolves the components of a request, either sent by a client or received by a server.
+func requestComponent(request *http.Request) componentFunc {
+	return func(name string) (string, error) {
+		scheme, host := request.URL.Scheme, request.URL.Host
+		if scheme == "" {
+			scheme = "http"
+			if request.TLS != nil {
+				scheme = "https"
+			}
+		}
+		if request.Host != "" {
+			host = request.Host
+		}
+
+		switch name {
+		case "@method":
+			return request.Method, nil
+		case "@target-uri":
+			return strings.ToLower(scheme) + "://" + host + requestTarget(request), nil
+		case "@authority":
+			return authority(scheme, host), nil
+		case "@scheme":
+			return strings.ToLower(scheme), nil
+		case "@request-target":
+			return requestTarget(request), nil
+		case "@path":
+			if path := request.URL.EscapedPath(); path != "" {
+				return path, nil
+			}
+			return "/", nil
+		case "@query":
+			return "?" + request.URL.RawQuery, nil
+		case "content-length":
+			if request.Header.Get("Content-Length") == "" && request.ContentLength >= 0 {
+				return strconv.FormatInt(request.ContentLength, 10), nil
+			}
+		}
+		return headerComponent(request.Header, name)
+	}
+}
+
+// responseComponent resolves the components of a response.
+func responseComponent(response *Response) componentFunc {
+	return func(name string) (string, error) {
+		if name == "@status" {
+			return strconv.Itoa(response.statusCode), nil
+		}
+		return headerComponent(response.headers, name)
+	}
+}
+
+// headerComponent returns the value of a header component, its values trimmed and joined with commas.
+func headerComponent(headers http.Header, name string) (string, error) {
+	if strings.HasPrefix(name, "@") {
+		return "", fmt.Errorf("unsupported derived component %q", name)
+	}
+	values := headers.Values(name)
+	if len(values) == 0 {
+		return "", fmt.Errorf("covered header %q is missing", name)
+	}
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.TrimSpace(value)
+	}
+	return strings.Join(trimmed, ", "), nil
+}
+
+// requestTarget returns the path and query of the request.
+func requestTarget(request *http.Request) string {
+	target := request.URL.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if request.URL.RawQuery != "" {
+		target += "?" + request.URL.RawQuery
+	}
+	return target
+}
+
+// authority returns the lowercased host, without the port when it is the default port of the scheme.
+func authority(scheme, host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.EqualFold(scheme, "http") && strings.HasSuffix(host, ":80"):
+		return strings.TrimSuffix(host, ":80")
+	case strings.EqualFold(scheme, "https") && strings.HasSuffix(host, ":443"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+// signatureBase builds the signature base from the covered components and the serialized signature parameters.
+func signatureBase(components []string, params string, component componentFunc) ([]byte, error) {
+	var base strings.Builder
+	for _, name := range components {
+		value, err := component(name)
+		if err != nil {
+			return nil, err
+		}
+		base.WriteString(strconv.Quote(name) + ": " + value + "\n")
+	}
+	base.WriteString(`"@signature-params": ` + params)
+	return []byte(base.String()), nil
+}
+
+// dictionaryMember is a member of a structured field dictionary with its raw serialized value.
+type dictionaryMember struct {
+	key   string
+	value string
+}
+
+// parseDictionary splits a structured field dictionary into its members without interpreting their values.
+func parseDictionary(field string) ([]dictionaryMember, error) {
+	var members []dictionaryMember
+	for _, raw := range splitOutside(field, ',') {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid member %q", raw)
+		}
+		members = append(members, dictionaryMember{key: strings.TrimSpace(key), value: strings.TrimSpace(value)})
+	}
+	return members, nil
+}
+
+// parseSignatureParams parses the covered components and the parameters of a Signature-Input member.
+// Component parameters such as ";sf" or ";req" are not supported.
+func parseSignatureParams(value string) ([]string, map[string]string, error) {
+	if !strings.HasPrefix(value, "(") {
+		return nil, nil, errors.New("signature parameters must start with an inner list")
+	}
+	end := strings.IndexByte(value, ')')
+	if end < 0 {
+		return nil, nil, errors.New("unterminated inner list")
+	}
+
+	var components []string
+	for _, item := range strings.Fields(value[1:end]) {
+		if strings.Contains(item, ";") {
+			return nil, nil, fmt.Errorf("unsupported component parameters in %s", item)
+		}
+		name, err := strconv.Unquote(item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid component %s", item)
+		}
+		components = append(components, name)
+	}
+
+	params := make(map[string]string)
+	for _, param := range splitOutside(value[end+1:], ';') {
+		if param = strings.TrimSpace(param); param == "" {
+			continue
+		}
+		key, val, _ := strings.Cut(param, "=")
+		params[key] = val
+	}
+	return components, params, nil
+}
+
+// splitOutside splits s at every separator that is outside a quoted string or a parenthesised inner list.
+func splitOutside(s string, separator byte) []string {
+	var parts []string
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == separator && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote returns the content of a quoted string parameter, or the parameter unchanged when it is not quoted.
+func unquote(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
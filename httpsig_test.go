@@ -0,0 +1,279 @@
+package webs
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc9421Request is the example request of RFC 9421, section B.2.
+const rfc9421Request = "POST /foo?param=Value&Pet=dog HTTP/1.1\r\n" +
+	"Host: example.com\r\n" +
+	"Date: Tue, 20 Apr 2021 02:07:55 GMT\r\n" +
+	"Content-Type: application/json\r\n" +
+	"Content-Digest: sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:\r\n" +
+	"Content-Length: 18\r\n" +
+	"\r\n" +
+	`{"hello": "world"}`
+
+// rfc9421Created is the creation time of the signatures of RFC 9421, section B.2.
+var rfc9421Created = time.Unix(1618884473, 0)
+
+// rfc9421HMACKey is the shared secret "test-shared-secret" of RFC 9421, section B.1.5.
+const rfc9421HMACKey = "uzvJfB4u3N0Jy4T7NZ75MDVcr8zSTInedJtkgcu46YW4XByzNJjxBdtjUkdJPBtbmHhIDi6pcl8jsasjlTMtDQ=="
+
+// rfc9421Ed25519Key is the PKCS#8 private key "test-key-ed25519" of RFC 9421, section B.1.4.
+const rfc9421Ed25519Key = "MC4CAQAwBQYDK2VwBCIEIJ+DYvh6SEqVTm50DFtMDoQikTmiCqirVv9mWG9qfSnF"
+
+// readRFC9421Request parses the example request of RFC 9421 as received by a server.
+func readRFC9421Request(t *testing.T) *http.Request {
+	t.Helper()
+	request, err := http.ReadRequest(bufio.NewReader(strings.NewReader(rfc9421Request)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return request
+}
+
+// TestMessageSigner_RFC9421 verifies the signatures against the HMAC-SHA256 and Ed25519 examples of RFC 9421.
+func TestMessageSigner_RFC9421(t *testing.T) {
+	secret, _ := base64.StdEncoding.DecodeString(rfc9421HMACKey)
+	der, _ := base64.StdEncoding.DecodeString(rfc9421Ed25519Key)
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	private := parsed.(ed25519.PrivateKey)
+
+	tests := []struct {
+		name          string
+		signer        *MessageSigner
+		verifier      *MessageVerifier
+		expectedInput string
+		expected      string
+	}{
+		{
+			name:          "hmacSHA256",
+			signer:        NewMessageSigner("test-shared-secret", NewHMACSHA256Key(secret), "date", "@authority", "content-type"),
+			verifier:      NewMessageVerifier("test-shared-secret", NewHMACSHA256Key(secret)),
+			expectedInput: `sig-b25=("date" "@authority" "content-type");created=1618884473;keyid="test-shared-secret"`,
+			expected:      "sig-b25=:pxcQw6G3AjtMBQjwo8XzkZf/bws5LelbaMk5rGIGtE8=:",
+		},
+		{
+			name:          "ed25519",
+			signer:        NewMessageSigner("test-key-ed25519", NewEd25519SigningKey(private), "date", "@method", "@path", "@authority", "content-type", "content-length"),
+			verifier:      NewMessageVerifier("test-key-ed25519", NewEd25519VerifyingKey(private.Public().(ed25519.PublicKey))),
+			expectedInput: `sig-b26=("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`,
+			expected:      "sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6vuQv5lIp5WPpBKRCw==:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := readRFC9421Request(t)
+			tt.signer.Label = strings.SplitN(tt.expected, "=", 2)[0]
+			tt.signer.now = func() time.Time { return rfc9421Created }
+			if err := tt.signer.Sign(request); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if input := request.Header.Get("Signature-Input"); input != tt.expectedInput {
+				t.Errorf("expected Signature-Input %s, got %s", tt.expectedInput, input)
+			}
+			if signature := request.Header.Get("Signature"); signature != tt.expected {
+				t.Errorf("expected Signature %s, got %s", tt.expected, signature)
+			}
+
+			received := readRFC9421Request(t)
+			received.Header.Set("Signature-Input", tt.expectedInput)
+			received.Header.Set("Signature", tt.expected)
+			if err := tt.verifier.VerifyRequest(received); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			received.Header.Set("Date", "Wed, 21 Apr 2021 02:07:55 GMT")
+			if err := tt.verifier.VerifyRequest(received); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature for a modified message, got %v", err)
+			}
+		})
+	}
+}
+
+// TestSetContentDigest verifies the Content-Digest header against the example of RFC 9530.
+func TestSetContentDigest(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://example.com/foo", strings.NewReader(`{"hello": "world"}`))
+	if err := SetContentDigest(request); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"
+	if digest := request.Header.Get("Content-Digest"); digest != expected {
+		t.Errorf("expected %s, got %s", expected, digest)
+	}
+
+	streamed, _ := http.NewRequest(http.MethodPost, "https://example.com/foo", io.NopCloser(strings.NewReader("body")))
+	if err := SetContentDigest(streamed); !errors.Is(err, ErrUnsignableBody) {
+		t.Errorf("expected ErrUnsignableBody, got %v", err)
+	}
+}
+
+// TestMessageSigner_Client verifies that requests signed by the client with ECDSA are verified by a server,
+// including the Content-Digest of their body.
+func TestMessageSigner_Client(t *testing.T) {
+	private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	verifier := NewMessageVerifier("partner", NewECDSAP256VerifyingKey(&private.PublicKey))
+	verifier.RequiredComponents = []string{"@method", "@target-uri", "content-digest"}
+	verifier.MaxAge = time.Minute
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifier.VerifyRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	signer := NewMessageSigner("partner", NewECDSAP256SigningKey(private), "@method", "@target-uri", "content-digest", "x-event")
+	client := NewClientBuilder().SetAuthenticator(signer).Build()
+
+	res, err := client.Post(server.URL+"/hooks?source=billing", http.Header{"X-Event": {"invoice.paid"}}, `{"id": 42}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusOK || res.String() != `{"id": 42}` {
+		t.Errorf("expected the body to be echoed, got %d %s", res.StatusCode(), res.String())
+	}
+
+	t.Run("missingComponent", func(t *testing.T) {
+		partial := NewMessageSigner("partner", NewECDSAP256SigningKey(private), "@method", "@target-uri")
+		res, _ := client.R().Auth(partial).Body("{}").Post(server.URL + "/hooks")
+		if res.StatusCode() != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", res.StatusCode())
+		}
+	})
+
+	t.Run("tamperedBody", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, server.URL+"/hooks", strings.NewReader(`{"id": 1}`))
+		request.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(`{"id": 1}`)), nil }
+		request.Header.Set("X-Event", "invoice.paid")
+		if err := signer.Sign(request); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		request.Body, request.GetBody = io.NopCloser(strings.NewReader(`{"id": 2}`)), nil
+		if err := verifier.VerifyRequest(request); !errors.Is(err, ErrContentDigestMismatch) {
+			t.Errorf("expected ErrContentDigestMismatch, got %v", err)
+		}
+	})
+}
+
+// TestResponse_VerifySignature verifies signatures covering the status, headers, and body of a response.
+func TestResponse_VerifySignature(t *testing.T) {
+	key := NewHMACSHA256Key([]byte("webhook-secret"))
+	now := time.Unix(1700000000, 0)
+
+	sign := func(params string, headers http.Header, body string) *Response {
+		response := NewResponse(http.StatusOK, headers, []byte(body))
+		components, _, _ := parseSignatureParams(params)
+		base, err := signatureBase(components, params, responseComponent(response))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		signature, _ := key.SignMessage(base)
+		headers.Set("Signature-Input", "sig1="+params)
+		headers.Set("Signature", "sig1=:"+base64.StdEncoding.EncodeToString(signature)+":")
+		return response
+	}
+	newHeaders := func() http.Header {
+		return http.Header{
+			"Content-Type":   {"application/json"},
+			"Content-Digest": {"sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"},
+		}
+	}
+	params := `("@status" "content-type" "content-digest");created=1700000000;expires=1700000300;keyid="server"`
+
+	tests := []struct {
+		name     string
+		response *Response
+		verifier *MessageVerifier
+		expected error
+	}{
+		{"valid", sign(params, newHeaders(), `{"hello": "world"}`), NewMessageVerifier("server", key), nil},
+		{"tamperedBody", sign(params, newHeaders(), `{"hello": "there"}`), NewMessageVerifier("server", key), ErrContentDigestMismatch},
+		{"unknownKey", sign(params, newHeaders(), `{"hello": "world"}`), NewMessageVerifier("other", key), ErrInvalidSignature},
+		{"wrongKey", sign(params, newHeaders(), `{"hello": "world"}`), NewMessageVerifier("server", NewHMACSHA256Key([]byte("wrong"))), ErrInvalidSignature},
+		{"unsigned", NewResponse(http.StatusOK, newHeaders(), nil), NewMessageVerifier("server", key), ErrInvalidSignature},
+		{"expired", sign(`("@status");created=1699990000;expires=1699990300;keyid="server"`, newHeaders(), ""), NewMessageVerifier("server", key), ErrInvalidSignature},
+		{"algorithmMismatch", sign(`("@status");created=1700000000;keyid="server";alg="ed25519"`, newHeaders(), ""), NewMessageVerifier("server", key), ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.verifier.now = func() time.Time { return now }
+			err := tt.response.VerifySignature(tt.verifier)
+			if tt.expected == nil && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	t.Run("maxAge", func(t *testing.T) {
+		verifier := NewMessageVerifier("server", key)
+		verifier.MaxAge = time.Minute
+		verifier.now = func() time.Time { return now.Add(2 * time.Minute) }
+		response := sign(`("@status");created=1700000000;keyid="server"`, newHeaders(), "")
+		if err := response.VerifySignature(verifier); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+}
+
+// TestMessageSigner_ChainedWithOAuth2 verifies that a signer chained after an OAuth2 token source covers the
+// Authorization header, and that a rejected token is still refreshed through the chain.
+func TestMessageSigner_ChainedWithOAuth2(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	key := NewHMACSHA256Key([]byte("partner-secret"))
+	verifier := NewMessageVerifier("partner", key)
+	verifier.RequiredComponents = []string{"@method", "@target-uri", "authorization"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifier.VerifyRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().
+		SetAuthenticator(ChainAuthenticators(
+			ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}),
+			NewMessageSigner("partner", key, "@method", "@target-uri", "authorization"),
+		)).
+		Build()
+
+	res, err := client.Get(server.URL+"/partner", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusOK || res.String() != "Bearer token-2" {
+		t.Errorf("expected a signed request with Bearer token-2, got %d %s", res.StatusCode(), res.String())
+	}
+}
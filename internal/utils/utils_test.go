@@ -39,58 +39,22 @@ func Test_mergeHeaders(t *testing.T) {
 	}
 }
 
-// Test_getRequestBody tests the GetRequestBody function for different content types and request bodies.
-func Test_getRequestBody(t *testing.T) {
-
-	t.Run("nobBodyNilResponse", func(t *testing.T) {
-
-		body, err := GetRequestBody("", nil)
-
-		if err != nil {
-			t.Errorf("expected no error, got %s", err)
-		}
-		if body != nil {
-			t.Errorf("expected body to be nil, got %s", body)
-		}
-	})
-
-	t.Run("bodyWithJsonResponse", func(t *testing.T) {
-		requestBody := []string{"foo", "bar"}
-
-		body, err := GetRequestBody("application/json", requestBody)
-		if err != nil {
-			t.Errorf("expected no error, got %s", err)
-		}
-		expectedBody := `["foo","bar"]`
-		if string(body) != expectedBody {
-			t.Errorf("invalid jsob body, expected, %s, got %s", expectedBody, body)
-		}
-	})
-
-	t.Run("bodyWithXmlResponse", func(t *testing.T) {
-		requestBody := []string{"foo", "bar"}
-
-		body, err := GetRequestBody("application/xml", requestBody)
-		if err != nil {
-			t.Errorf("expected no error, got %s", err)
-		}
-		expectedBody := `<string>foo</string><string>bar</string>`
-		if string(body) != expectedBody {
-			t.Errorf("expected %s, got %s", expectedBody, body)
-		}
-	})
-
-	t.Run("defaultResponse", func(t *testing.T) {
-		requestBody := []string{"foo", "bar"}
-
-		body, err := GetRequestBody("", requestBody)
-		if err != nil {
-			t.Errorf("expected no error, got %s", err)
-		}
-		expectedBody := `["foo","bar"]`
-		if string(body) != expectedBody {
-			t.Errorf("invalid jsob body, expected, %s, got %s", expectedBody, body)
-		}
-	})
+// Test_replaceHeaders verifies that ReplaceHeaders keeps the original headers and replaces those set again.
+func Test_replaceHeaders(t *testing.T) {
+	headers := make(http.Header)
+	headers.Add("Content-Type", "application/json")
+	headers.Add("Accept", "application/json")
+	newHeaders := make(http.Header)
+	newHeaders.Add("content-type", "application/xml")
 
+	results := ReplaceHeaders(headers, newHeaders)
+	if values := results.Values("Content-Type"); len(values) != 1 || values[0] != "application/xml" {
+		t.Errorf("expected Content-Type to be application/xml, got %v", values)
+	}
+	if results.Get("Accept") != "application/json" {
+		t.Errorf("expected Accept to be application/json, got %s", results.Get("Accept"))
+	}
+	if headers.Get("Content-Type") != "application/json" {
+		t.Errorf("expected original headers to be unchanged, got %s", headers.Get("Content-Type"))
+	}
 }
@@ -0,0 +1,20 @@
+package webs
+
+import (
+	"net/http"
+)
+
+// Handler processes an outgoing HTTP request and produces its Response.
+type Handler func(request *http.Request) (*Response, error)
+
+// Middleware wraps a Handler to add behaviour around request execution.
+// A middleware may modify the outgoing request, return a synthetic Response without calling next, or inspect the result.
+type Middleware func(next Handler) Handler
+
+// chain composes the middlewares around handler so that the first middleware is the outermost one.
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
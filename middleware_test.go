@@ -0,0 +1,100 @@
+package webs
+
+import (
+	"github.com/h2non/gock"
+	"net/http"
+	"testing"
+)
+
+// TestMiddleware_ModifiesRequest verifies that a middleware can add headers to the outgoing request.
+func TestMiddleware_ModifiesRequest(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/").
+		MatchHeader("X-Request-Id", "abc-123").
+		Reply(200)
+
+	client := NewClientBuilder().
+		Use(func(next Handler) Handler {
+			return func(request *http.Request) (*Response, error) {
+				request.Header.Set("X-Request-Id", "abc-123")
+				return next(request)
+			}
+		}).
+		Build()
+	gock.InterceptClient(client.client)
+
+	res, err := client.Get("https://server.com", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status code 200, got %d", res.StatusCode())
+	}
+}
+
+// TestMiddleware_ShortCircuits verifies that a middleware can answer with a synthetic Response without sending the request.
+func TestMiddleware_ShortCircuits(t *testing.T) {
+	client := NewClientBuilder().
+		Use(func(next Handler) Handler {
+			return func(request *http.Request) (*Response, error) {
+				return NewResponse(http.StatusTeapot, nil, []byte("cached")), nil
+			}
+		}).
+		Build()
+
+	res, err := client.Get("https://unreachable.invalid", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusTeapot {
+		t.Errorf("expected status code 418, got %d", res.StatusCode())
+	}
+	if res.Status() != "418 I'm a teapot" {
+		t.Errorf("expected status '418 I'm a teapot', got %s", res.Status())
+	}
+	if res.String() != "cached" {
+		t.Errorf("expected body 'cached', got %s", res.String())
+	}
+}
+
+// TestMiddleware_Order verifies that middleware runs in registration order and can inspect the result.
+func TestMiddleware_Order(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/").
+		Reply(204)
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(request *http.Request) (*Response, error) {
+				calls = append(calls, name+":before")
+				res, err := next(request)
+				if err == nil {
+					calls = append(calls, name+":after:"+res.Status())
+				}
+				return res, err
+			}
+		}
+	}
+
+	client := NewClientBuilder().Use(record("outer"), record("inner")).Build()
+	gock.InterceptClient(client.client)
+
+	if _, err := client.Get("https://server.com", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"outer:before", "inner:before", "inner:after:204 No Content", "outer:after:204 No Content"}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+}
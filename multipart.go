@@ -0,0 +1,173 @@
+package webs
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// defaultFileContentType is the Content-Type of file parts whose type cannot be determined.
+const defaultFileContentType = "application/octet-stream"
+
+// quoteEscaper escapes backslashes and double quotes in Content-Disposition parameters.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Multipart is a multipart/form-data request body made of text fields and file parts.
+// It is streamed through a pipe when the request is sent, so file contents are never buffered in memory.
+// Because readers can only be consumed once, requests carrying a Multipart body are not retried.
+type Multipart struct {
+	parts []multipartPart
+}
+
+// multipartPart describes a single part of a Multipart body. Exactly one of value, reader, or path is used.
+type multipartPart struct {
+	header textproto.MIMEHeader
+	value  string
+	reader io.Reader
+	path   string
+}
+
+// NewMultipart creates an empty Multipart body.
+func NewMultipart() *Multipart {
+	return &Multipart{}
+}
+
+// AddField adds a text field with the given name and value.
+func (m *Multipart) AddField(name, value string) *Multipart {
+	m.parts = append(m.parts, multipartPart{
+		header: formDataHeader(name, ""),
+		value:  value,
+	})
+	return m
+}
+
+// AddFile adds a file part whose content is read from reader. Entries in header are added to the part headers,
+// replacing the generated Content-Type when set. The header may be nil.
+func (m *Multipart) AddFile(fieldName, fileName string, reader io.Reader, header textproto.MIMEHeader) *Multipart {
+	m.parts = append(m.parts, multipartPart{
+		header: fileHeader(fieldName, fileName, defaultFileContentType, header),
+		reader: reader,
+	})
+	return m
+}
+
+// AddFileFromPath adds a file part whose content is read from the file at path when the request is sent.
+// The Content-Type is derived from the file extension unless header overrides it. The header may be nil.
+func (m *Multipart) AddFileFromPath(fieldName, path string, header textproto.MIMEHeader) *Multipart {
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = defaultFileContentType
+	}
+	m.parts = append(m.parts, multipartPart{
+		header: fileHeader(fieldName, filepath.Base(path), contentType, header),
+		path:   path,
+	})
+	return m
+}
+
+// AddPart adds a part with fully custom headers whose content is read from reader.
+func (m *Multipart) AddPart(header textproto.MIMEHeader, reader io.Reader) *Multipart {
+	m.parts = append(m.parts, multipartPart{
+		header: header,
+		reader: reader,
+	})
+	return m
+}
+
+// formDataHeader returns the Content-Disposition header of a form-data part.
+func formDataHeader(name, fileName string) textproto.MIMEHeader {
+	disposition := fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(name))
+	if fileName != "" {
+		disposition += fmt.Sprintf(`; filename="%s"`, quoteEscaper.Replace(fileName))
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", disposition)
+	return header
+}
+
+// fileHeader returns the headers of a file part, applying the custom entries on top of the generated ones.
+func fileHeader(fieldName, fileName, contentType string, custom textproto.MIMEHeader) textproto.MIMEHeader {
+	header := formDataHeader(fieldName, fileName)
+	header.Set("Content-Type", contentType)
+	for key, values := range custom {
+		header[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+	return header
+}
+
+// reader returns a lazily started stream of the encoded body along with its Content-Type, boundary included.
+func (m *Multipart) reader() (io.ReadCloser, string) {
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+	return &multipartReader{
+		multipart:  m,
+		pipeReader: pipeReader,
+		pipeWriter: pipeWriter,
+		writer:     writer,
+	}, writer.FormDataContentType()
+}
+
+// writeTo encodes every part into writer and closes it, writing the final boundary.
+func (m *Multipart) writeTo(writer *multipart.Writer) error {
+	for _, part := range m.parts {
+		if err := part.writeTo(writer); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// writeTo encodes the part into writer, opening its file when the part is backed by a path.
+func (p multipartPart) writeTo(writer *multipart.Writer) error {
+	partWriter, err := writer.CreatePart(p.header)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.reader != nil:
+		_, err = io.Copy(partWriter, p.reader)
+	case p.path != "":
+		var file *os.File
+		file, err = os.Open(p.path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(partWriter, file)
+		_ = file.Close()
+	default:
+		_, err = io.WriteString(partWriter, p.value)
+	}
+	return err
+}
+
+// multipartReader streams a Multipart body through a pipe. The writing goroutine starts on the first Read so a request
+// that is never sent does not leak it, and closing the reader stops the writer.
+type multipartReader struct {
+	multipart  *Multipart
+	pipeReader *io.PipeReader
+	pipeWriter *io.PipeWriter
+	writer     *multipart.Writer
+	once       sync.Once
+}
+
+// Read starts the encoding goroutine on first use and reads the next chunk of the encoded body.
+func (r *multipartReader) Read(p []byte) (int, error) {
+	r.once.Do(func() {
+		go func() {
+			_ = r.pipeWriter.CloseWithError(r.multipart.writeTo(r.writer))
+		}()
+	})
+	return r.pipeReader.Read(p)
+}
+
+// Close closes the pipe, making any pending write fail.
+func (r *multipartReader) Close() error {
+	return r.pipeReader.Close()
+}
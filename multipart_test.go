@@ -0,0 +1,117 @@
+package webs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestClient_Multipart verifies that fields, reader files, and path files are streamed as multipart/form-data.
+func TestClient_Multipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, []byte(`{"rows":1}`), 0o600); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TransferEncoding) == 0 || r.TransferEncoding[0] != "chunked" {
+			t.Errorf("expected chunked transfer encoding, got %v", r.TransferEncoding)
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("expected multipart request, got %v", err)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			content, _ := io.ReadAll(part)
+			_, _ = fmt.Fprintf(w, "%s|%s|%s|%s|%s\n", part.FormName(), part.FileName(),
+				part.Header.Get("Content-Type"), part.Header.Get("X-Checksum"), content)
+		}
+	}))
+	defer server.Close()
+
+	checksum := textproto.MIMEHeader{}
+	checksum.Set("X-Checksum", "abc")
+
+	body := NewMultipart().
+		AddField("title", "Quarterly").
+		AddFile("avatar", "me.png", strings.NewReader("png-bytes"), nil).
+		AddFileFromPath("report", path, checksum)
+
+	client := NewClientBuilder().Build()
+	res, err := client.Post(server.URL, nil, body)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "title||||Quarterly\n" +
+		"avatar|me.png|application/octet-stream||png-bytes\n" +
+		`report|report.json|application/json|abc|{"rows":1}` + "\n"
+	if res.String() != expected {
+		t.Errorf("expected parts:\n%s\ngot:\n%s", expected, res.String())
+	}
+}
+
+// TestClient_MultipartCustomPart verifies that a part can be added with fully custom headers.
+func TestClient_MultipartCustomPart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("expected multipart request, got %v", err)
+			return
+		}
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s|%s|%s", part.FormName(), part.Header.Get("Content-Type"), part.Header.Get("Content-Language"))
+	}))
+	defer server.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="meta"`)
+	header.Set("Content-Type", "application/json")
+	header.Set("Content-Language", "en")
+
+	client := NewClientBuilder().Build()
+	res, err := client.Post(server.URL, nil, NewMultipart().AddPart(header, strings.NewReader(`{"a":1}`)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "meta|application/json|en" {
+		t.Errorf("expected custom part headers, got %s", res.String())
+	}
+}
+
+// TestClient_MultipartMissingFile verifies that a missing file aborts the request with the file system error.
+func TestClient_MultipartMissingFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	body := NewMultipart().AddFileFromPath("file", filepath.Join(t.TempDir(), "missing.txt"), nil)
+
+	client := NewClientBuilder().Build()
+	_, err := client.Post(server.URL, nil, body)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package webs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTokenExpiryDelta is how long before its expiry a cached access token is considered expired.
+	defaultTokenExpiryDelta = 10 * time.Second
+
+	// defaultTokenTimeout bounds a token request, which is not cancelled when the request waiting for it is.
+	defaultTokenTimeout = 30 * time.Second
+)
+
+// ErrOAuth2 is matched by an OAuth2Error.
+var ErrOAuth2 = errors.New("oauth2 token request failed")
+
+// OAuth2Error is returned when the token endpoint rejects a token request.
+type OAuth2Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// Error returns a description of the error including the OAuth2 error code, if any.
+func (e *OAuth2Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s: status %d", ErrOAuth2, e.StatusCode)
+	}
+	if e.Description == "" {
+		return fmt.Sprintf("%s: %s", ErrOAuth2, e.Code)
+	}
+	return fmt.Sprintf("%s: %s: %s", ErrOAuth2, e.Code, e.Description)
+}
+
+// Unwrap returns ErrOAuth2 so the error can be matched with errors.Is.
+func (e *OAuth2Error) Unwrap() error {
+	return ErrOAuth2
+}
+
+// OAuth2Config describes the token endpoint and the client credentials used to obtain access tokens.
+type OAuth2Config struct {
+	// TokenURL is the URL of the token endpoint.
+	TokenURL string
+
+	// ClientID and ClientSecret identify the client. They are sent with HTTP basic authentication.
+	ClientID     string
+	ClientSecret string
+
+	// AuthInBody sends the client credentials as form parameters instead of with HTTP basic authentication.
+	AuthInBody bool
+
+	// Scopes are the scopes requested for the access token.
+	Scopes []string
+
+	// EndpointParams are additional form parameters sent to the token endpoint, such as "audience".
+	EndpointParams url.Values
+
+	// ExpiryDelta is how long before its expiry a token is refreshed. Defaults to 10 seconds.
+	ExpiryDelta time.Duration
+
+	// HTTPClient sends the token requests. Defaults to the transport of the Client sending the request being
+	// authenticated, so its proxy and TLS settings also apply to the token endpoint, or to http.DefaultClient when
+	// Token is called directly.
+	HTTPClient *http.Client
+}
+
+// Token is an OAuth2 access token.
+type Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	// Expiry is the time the access token expires at. It is zero when the token endpoint did not specify one.
+	Expiry time.Time
+}
+
+// valid reports whether the token can still be used at now, given the expiry delta.
+func (t *Token) valid(now time.Time, delta time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || now.Add(delta).Before(t.Expiry)
+}
+
+// OAuth2TokenSource is an Authenticator that sends OAuth2 access tokens, obtaining them from the token endpoint and
+// caching them until shortly before they expire. Concurrent requests share a single token request. When a request
+// is answered with 401 Unauthorized, the token it carried is invalidated and the request is retried once.
+type OAuth2TokenSource struct {
+	config       OAuth2Config
+	grantType    string
+	mu           sync.Mutex
+	token        *Token
+	refreshToken string
+	call         *tokenCall
+}
+
+// tokenCall is a token request in flight, shared by every caller waiting for a token.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// ClientCredentials returns an OAuth2TokenSource that obtains tokens with the client_credentials grant.
+func ClientCredentials(config OAuth2Config) *OAuth2TokenSource {
+	return &OAuth2TokenSource{config: config, grantType: "client_credentials"}
+}
+
+// RefreshToken returns an OAuth2TokenSource that obtains tokens with the refresh_token grant. A refresh token
+// rotated by the token endpoint replaces refreshToken for later requests.
+func RefreshToken(config OAuth2Config, refreshToken string) *OAuth2TokenSource {
+	return &OAuth2TokenSource{config: config, grantType: "refresh_token", refreshToken: refreshToken}
+}
+
+// Authenticate sends the current access token in the Authorization header.
+func (s *OAuth2TokenSource) Authenticate(request *http.Request) error {
+	token, err := s.Token(request.Context())
+	if err != nil {
+		return err
+	}
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	request.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+	return nil
+}
+
+// Invalidate discards the cached token if it is the one carried by the rejected request, so that concurrent
+// rejections of the same token cause a single token request.
+func (s *OAuth2TokenSource) Invalidate(request *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return
+	}
+	if strings.HasSuffix(request.Header.Get("Authorization"), " "+s.token.AccessToken) {
+		s.token = nil
+	}
+}
+
+// Token returns the cached access token, or requests a new one from the token endpoint when it has expired.
+// Cancelling ctx stops waiting for the token but does not cancel the token request shared with other callers.
+func (s *OAuth2TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	if s.token.valid(time.Now(), s.getExpiryDelta()) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	call := s.call
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		s.call = call
+		go s.fetch(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch requests a token, caches it on success, and releases the callers waiting for it.
+func (s *OAuth2TokenSource) fetch(ctx context.Context, call *tokenCall) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTokenTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	refreshToken := s.refreshToken
+	s.mu.Unlock()
+
+	token, err := s.requestToken(ctx, refreshToken)
+
+	s.mu.Lock()
+	if err == nil {
+		s.token = token
+		if token.RefreshToken != "" {
+			s.refreshToken = token.RefreshToken
+		}
+	}
+	s.call = nil
+	s.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
+
+// requestToken sends a token request for the grant of the source to the token endpoint.
+func (s *OAuth2TokenSource) requestToken(ctx context.Context, refreshToken string) (*Token, error) {
+	form := url.Values{"grant_type": {s.grantType}}
+	if s.grantType == "refresh_token" {
+		form.Set("refresh_token", refreshToken)
+	}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	for key, values := range s.config.EndpointParams {
+		form[key] = values
+	}
+	if s.config.AuthInBody {
+		form.Set("client_id", s.config.ClientID)
+		if s.config.ClientSecret != "" {
+			form.Set("client_secret", s.config.ClientSecret)
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", ContentTypeForm)
+	request.Header.Set("Accept", ContentTypeJSON)
+	if !s.config.AuthInBody {
+		request.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	response, err := s.getHTTPClient(ctx).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	if err != nil {
+		return nil, err
+	}
+	if !isSuccess(response.StatusCode) {
+		oauthErr := &OAuth2Error{StatusCode: response.StatusCode}
+		_ = json.Unmarshal(body, oauthErr)
+		return nil, oauthErr
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: invalid token response: %w", ErrOAuth2, err)
+	}
+	if payload.AccessToken == "" {
+		return nil, fmt.Errorf("%w: token response without access_token", ErrOAuth2)
+	}
+
+	token := &Token{AccessToken: payload.AccessToken, TokenType: payload.TokenType, RefreshToken: payload.RefreshToken}
+	if payload.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// getExpiryDelta returns the configured expiry delta or the default value if not set.
+func (s *OAuth2TokenSource) getExpiryDelta() time.Duration {
+	if s.config.ExpiryDelta > 0 {
+		return s.config.ExpiryDelta
+	}
+	return defaultTokenExpiryDelta
+}
+
+// getHTTPClient returns the configured HTTP client, a client using the transport stored in ctx, or http.DefaultClient.
+func (s *OAuth2TokenSource) getHTTPClient(ctx context.Context) *http.Client {
+	if s.config.HTTPClient != nil {
+		return s.config.HTTPClient
+	}
+	if transport, ok := transportFromContext(ctx); ok {
+		return &http.Client{Transport: transport}
+	}
+	return http.DefaultClient
+}
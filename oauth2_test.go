@@ -0,0 +1,289 @@
+package webs
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer is a local token endpoint issuing access tokens named "token-N", N being the number of the request.
+type tokenServer struct {
+	*httptest.Server
+	calls     atomic.Int32
+	expiresIn int
+	delay     time.Duration
+	forms     chan map[string]string
+}
+
+// newTokenServer starts a token endpoint that records the form of every request and issues tokens valid for expiresIn seconds.
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{expiresIn: expiresIn, forms: make(chan map[string]string, 100)}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := ts.calls.Add(1)
+		time.Sleep(ts.delay)
+
+		_ = r.ParseForm()
+		form := map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+		if id, secret, ok := r.BasicAuth(); ok {
+			form["basic"] = id + ":" + secret
+		}
+		ts.forms <- form
+
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		if form["basic"] != "client:secret" && form["client_secret"] != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "token-" + strconv.Itoa(int(n)),
+			"token_type":    "bearer",
+			"expires_in":    ts.expiresIn,
+			"refresh_token": "refresh-" + strconv.Itoa(int(n)),
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newProtectedServer starts an API server that echoes the Authorization header and rejects the tokens in rejected.
+func newProtectedServer(t *testing.T, rejected ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		authorization := r.Header.Get("Authorization")
+		for _, token := range rejected {
+			if authorization == "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		_, _ = w.Write([]byte(authorization))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// TestClientCredentials verifies that tokens are obtained with the client_credentials grant and cached.
+func TestClientCredentials(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api, _ := newProtectedServer(t)
+
+	client := NewClientBuilder().
+		SetAuthenticator(ClientCredentials(OAuth2Config{
+			TokenURL:       tokens.URL,
+			ClientID:       "client",
+			ClientSecret:   "secret",
+			Scopes:         []string{"read", "write"},
+			EndpointParams: map[string][]string{"audience": {"api"}},
+		})).
+		Build()
+
+	for i := 0; i < 3; i++ {
+		res, err := client.Get(api.URL, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "Bearer token-1" {
+			t.Errorf("expected Bearer token-1, got %s", res.String())
+		}
+	}
+	if calls := tokens.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 token request, got %d", calls)
+	}
+
+	form := <-tokens.forms
+	expected := map[string]string{"grant_type": "client_credentials", "scope": "read write", "audience": "api", "basic": "client:secret"}
+	for key, value := range expected {
+		if form[key] != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, form[key])
+		}
+	}
+}
+
+// TestClientCredentials_ClientTransport verifies that token requests use the transport of the client they authenticate
+// for, so a token endpoint trusted only through AddRootCAs is reachable.
+func TestClientCredentials_ClientTransport(t *testing.T) {
+	tokens := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		_, _ = w.Write([]byte(`{"access_token":"private","token_type":"bearer"}`))
+	}))
+	defer tokens.Close()
+	api, _ := newProtectedServer(t)
+
+	client := NewClientBuilder().
+		AddRootCAs(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tokens.Certificate().Raw})).
+		SetAuthenticator(ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})).
+		Build()
+
+	res, err := client.Get(api.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "Bearer private" {
+		t.Errorf("expected Bearer private, got %s", res.String())
+	}
+}
+
+// TestClientCredentials_AuthInBody verifies that the client credentials can be sent as form parameters.
+func TestClientCredentials_AuthInBody(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	source := ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret", AuthInBody: true})
+
+	if _, err := source.Token(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	form := <-tokens.forms
+	if form["client_id"] != "client" || form["client_secret"] != "secret" || form["basic"] != "" {
+		t.Errorf("expected credentials in body only, got %v", form)
+	}
+}
+
+// TestOAuth2TokenSource_Expiry verifies that a token is requested again once it is about to expire.
+func TestOAuth2TokenSource_Expiry(t *testing.T) {
+	tokens := newTokenServer(t, 5)
+	source := ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})
+
+	first, _ := source.Token(context.Background())
+	second, _ := source.Token(context.Background())
+	if first.AccessToken != "token-1" || second.AccessToken != "token-2" {
+		t.Errorf("expected token-1 then token-2, got %s then %s", first.AccessToken, second.AccessToken)
+	}
+
+	source.config.ExpiryDelta = time.Second
+	third, _ := source.Token(context.Background())
+	if third.AccessToken != "token-2" {
+		t.Errorf("expected token-2 to be cached, got %s", third.AccessToken)
+	}
+}
+
+// TestOAuth2TokenSource_Concurrent verifies that concurrent requests share a single token request.
+func TestOAuth2TokenSource_Concurrent(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	tokens.delay = 50 * time.Millisecond
+	api, _ := newProtectedServer(t)
+
+	client := NewClientBuilder().
+		SetAuthenticator(ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})).
+		Build()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(api.URL, nil)
+			if err != nil {
+				t.Errorf("expected no error, got %v", err)
+				return
+			}
+			if res.String() != "Bearer token-1" {
+				t.Errorf("expected Bearer token-1, got %s", res.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls := tokens.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 token request, got %d", calls)
+	}
+}
+
+// TestOAuth2TokenSource_Unauthorized verifies that a rejected token is invalidated and the request retried once.
+func TestOAuth2TokenSource_Unauthorized(t *testing.T) {
+	t.Run("retriedWithNewToken", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600)
+		api, calls := newProtectedServer(t, "token-1")
+		client := NewClientBuilder().
+			SetAuthenticator(ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})).
+			Build()
+
+		res, err := client.Post(api.URL, nil, map[string]string{"name": "webs"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "Bearer token-2" {
+			t.Errorf("expected Bearer token-2, got %s", res.String())
+		}
+		if calls.Load() != 2 {
+			t.Errorf("expected 2 API requests, got %d", calls.Load())
+		}
+	})
+
+	t.Run("retriedOnce", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600)
+		api, calls := newProtectedServer(t, "token-1", "token-2", "token-3")
+		client := NewClientBuilder().
+			SetAuthenticator(ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})).
+			Build()
+
+		res, err := client.Get(api.URL, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.StatusCode() != http.StatusUnauthorized {
+			t.Errorf("expected status 401, got %d", res.StatusCode())
+		}
+		if calls.Load() != 2 || tokens.calls.Load() != 2 {
+			t.Errorf("expected 2 API and 2 token requests, got %d and %d", calls.Load(), tokens.calls.Load())
+		}
+	})
+}
+
+// TestRefreshToken verifies that tokens are obtained with the refresh_token grant and rotated refresh tokens are used.
+func TestRefreshToken(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api, _ := newProtectedServer(t, "token-1")
+	client := NewClientBuilder().
+		SetAuthenticator(RefreshToken(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"}, "initial")).
+		Build()
+
+	res, err := client.Get(api.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "Bearer token-2" {
+		t.Errorf("expected Bearer token-2, got %s", res.String())
+	}
+
+	for _, expected := range []string{"initial", "refresh-1"} {
+		form := <-tokens.forms
+		if form["grant_type"] != "refresh_token" || form["refresh_token"] != expected {
+			t.Errorf("expected refresh_token grant with %s, got %v", expected, form)
+		}
+	}
+}
+
+// TestOAuth2TokenSource_Error verifies that errors returned by the token endpoint are reported as an *OAuth2Error.
+func TestOAuth2TokenSource_Error(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	api, calls := newProtectedServer(t)
+	client := NewClientBuilder().
+		SetAuthenticator(ClientCredentials(OAuth2Config{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "wrong"})).
+		Build()
+
+	_, err := client.Get(api.URL, nil)
+	if !errors.Is(err, ErrOAuth2) {
+		t.Fatalf("expected ErrOAuth2, got %v", err)
+	}
+	var oauthErr *OAuth2Error
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_client" || oauthErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected invalid_client error with status 401, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no API request, got %d", calls.Load())
+	}
+}
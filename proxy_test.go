@@ -0,0 +1,235 @@
+package webs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+// proxyServer returns a test server acting as a forward HTTP proxy. It answers on behalf of the target and reports the
+// requested URL and the Proxy-Authorization header it received.
+func proxyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proxied-URL", r.URL.String())
+		w.Header().Set("X-Proxy-Authorization", r.Header.Get("Proxy-Authorization"))
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClientBuilder_SetProxy verifies that requests go through the configured proxy with basic auth credentials.
+func TestClientBuilder_SetProxy(t *testing.T) {
+	proxy := proxyServer(t)
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	client := NewClientBuilder().SetProxy(proxyURL.String()).Build()
+
+	res, err := client.Get("http://target.example/path", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "proxied" {
+		t.Errorf("expected response from proxy, got %s", res.String())
+	}
+	if res.Headers().Get("X-Proxied-URL") != "http://target.example/path" {
+		t.Errorf("expected proxied URL http://target.example/path, got %s", res.Headers().Get("X-Proxied-URL"))
+	}
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	if res.Headers().Get("X-Proxy-Authorization") != expected {
+		t.Errorf("expected Proxy-Authorization %s, got %s", expected, res.Headers().Get("X-Proxy-Authorization"))
+	}
+}
+
+// TestClientBuilder_SetNoProxy verifies that hosts of the bypass list are reached directly.
+func TestClientBuilder_SetNoProxy(t *testing.T) {
+	proxy := proxyServer(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	defer target.Close()
+
+	client := NewClientBuilder().SetProxy(proxy.URL).SetNoProxy("127.0.0.0/8").Build()
+
+	res, err := client.Get(target.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "direct" {
+		t.Errorf("expected direct response, got %s", res.String())
+	}
+}
+
+// TestClientBuilder_ProxyFromEnvironment verifies that the proxy environment variables are honoured by default.
+func TestClientBuilder_ProxyFromEnvironment(t *testing.T) {
+	transport := NewClientBuilder().Build().client.Transport.(*http.Transport)
+	if transport.Proxy == nil {
+		t.Fatal("expected a proxy function, got nil")
+	}
+
+	disabled := NewClientBuilder().DisableProxy().Build().client.Transport.(*http.Transport)
+	if disabled.Proxy != nil {
+		t.Error("expected no proxy function when the proxy is disabled")
+	}
+}
+
+// TestClientBuilder_InvalidProxy verifies that an unsupported proxy scheme is reported by every request.
+func TestClientBuilder_InvalidProxy(t *testing.T) {
+	client := NewClientBuilder().SetProxy("ftp://proxy.example").Build()
+	if _, err := client.Get("http://target.example", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+// TestBypassProxy checks the matching rules of the bypass list.
+func TestBypassProxy(t *testing.T) {
+	tests := []struct {
+		entry  string
+		target string
+		want   bool
+	}{
+		{"internal.example", "http://internal.example/x", true},
+		{"internal.example", "http://api.internal.example/x", true},
+		{"internal.example", "http://notinternal.example/x", false},
+		{".example.com", "https://api.example.com", true},
+		{".example.com", "https://example.com", false},
+		{"*.example.com", "https://api.example.com", true},
+		{"*.example.com", "https://example.com", false},
+		{"*.example.com", "https://example.org", false},
+		{"10.0.0.0/8", "http://10.1.2.3:8080", true},
+		{"192.168.1.1", "http://192.168.1.1", true},
+		{"192.168.1.1", "http://192.168.1.10", false},
+		{"[::1]", "http://[::1]:8080", true},
+		{"host.example:8443", "https://host.example:8443", true},
+		{"host.example:8443", "https://host.example", false},
+		{"*", "https://anything.example", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry+" "+tt.target, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			if got := bypassProxy([]string{tt.entry}, target); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// socks5Server starts a minimal SOCKS5 proxy supporting username/password authentication and the CONNECT command.
+// It returns the proxy address and a channel receiving the credentials and target of each connection.
+func socks5Server(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	seen := make(chan string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSOCKS5(conn, seen)
+		}
+	}()
+	return listener.Addr().String(), seen
+}
+
+// serveSOCKS5 handles a single SOCKS5 connection, relaying it to the requested target.
+func serveSOCKS5(conn net.Conn, seen chan<- string) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	_, _ = conn.Write([]byte{5, 2})
+
+	readField := func() string {
+		size := make([]byte, 1)
+		_, _ = io.ReadFull(conn, size)
+		field := make([]byte, size[0])
+		_, _ = io.ReadFull(conn, field)
+		return string(field)
+	}
+	_, _ = io.ReadFull(conn, make([]byte, 1))
+	credentials := readField() + ":" + readField()
+	_, _ = conn.Write([]byte{1, 0})
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		_, _ = io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		host = readField()
+	default:
+		return
+	}
+	portBytes := make([]byte, 2)
+	_, _ = io.ReadFull(conn, portBytes)
+	address := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(portBytes))))
+
+	seen <- credentials + "@" + address
+	target, err := net.Dial("tcp", address)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer func() {
+		_ = target.Close()
+	}()
+	_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+	go func() {
+		_, _ = io.Copy(target, conn)
+	}()
+	_, _ = io.Copy(conn, target)
+}
+
+// TestClientBuilder_SetSOCKS5Proxy verifies that requests are tunnelled through a SOCKS5 proxy with credentials.
+func TestClientBuilder_SetSOCKS5Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tunnelled"))
+	}))
+	defer target.Close()
+
+	address, seen := socks5Server(t)
+	client := NewClientBuilder().SetSOCKS5Proxy(address, "user", "secret").Build()
+
+	res, err := client.Get(target.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "tunnelled" {
+		t.Errorf("expected tunnelled response, got %s", res.String())
+	}
+
+	expected := "user:secret@" + target.Listener.Addr().String()
+	if got := <-seen; got != expected {
+		t.Errorf("expected proxy to see %s, got %s", expected, got)
+	}
+}
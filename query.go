@@ -0,0 +1,137 @@
+package webs
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodeQuery converts query into url.Values. It accepts url.Values, map[string]string, and structs, or pointers to
+// them. Struct fields are named by their `url` tag, falling back to the field name, and support the "omitempty" option;
+// a tag of "-" skips the field. Slices produce repeated parameters, time.Time values are formatted as RFC 3339, and
+// embedded structs are flattened.
+func EncodeQuery(query interface{}) (url.Values, error) {
+	switch value := query.(type) {
+	case nil:
+		return url.Values{}, nil
+	case url.Values:
+		return value, nil
+	case map[string]string:
+		values := make(url.Values, len(value))
+		for key, item := range value {
+			values.Set(key, item)
+		}
+		return values, nil
+	}
+
+	reflected := reflect.ValueOf(query)
+	for reflected.Kind() == reflect.Pointer {
+		if reflected.IsNil() {
+			return url.Values{}, nil
+		}
+		reflected = reflected.Elem()
+	}
+	if reflected.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode %T as query parameters", query)
+	}
+
+	values := make(url.Values)
+	if err := encodeStruct(values, reflected); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// encodeStruct adds the exported fields of the struct value to values.
+func encodeStruct(values url.Values, value reflect.Value) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get("url")
+		if tag == "-" {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if field.Anonymous && tag == "" && reflect.Indirect(fieldValue).Kind() == reflect.Struct {
+			if fieldValue.Kind() == reflect.Pointer && (fieldValue.IsNil() || !field.IsExported()) {
+				continue
+			}
+			if err := encodeStruct(values, reflect.Indirect(fieldValue)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		omitEmpty := options == "omitempty"
+
+		if name == "" {
+			name = field.Name
+		}
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		if err := encodeField(values, name, fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeField adds the value of a single field to values, repeating the parameter for slices and arrays.
+func encodeField(values url.Values, name string, value reflect.Value) error {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		for i := 0; i < value.Len(); i++ {
+			if err := encodeField(values, name, value.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	formatted, err := formatQueryValue(value)
+	if err != nil {
+		return fmt.Errorf("query parameter %s: %w", name, err)
+	}
+	values.Add(name, formatted)
+	return nil
+}
+
+// formatQueryValue converts a scalar value into its query string representation.
+func formatQueryValue(value reflect.Value) (string, error) {
+	if timestamp, ok := value.Interface().(time.Time); ok {
+		return timestamp.Format(time.RFC3339), nil
+	}
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", value.Type())
+	}
+}
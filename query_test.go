@@ -0,0 +1,75 @@
+package webs
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// pagination is embedded in query structs to check that embedded fields are flattened.
+type pagination struct {
+	Page    int `url:"page,omitempty"`
+	PerPage int `url:"per_page,omitempty"`
+}
+
+// TestEncodeQuery_Struct verifies that tagged struct fields are encoded as query parameters.
+func TestEncodeQuery_Struct(t *testing.T) {
+	active := true
+	query := struct {
+		pagination
+		Search  string    `url:"q"`
+		Tags    []string  `url:"tag"`
+		Active  *bool     `url:"active"`
+		Since   time.Time `url:"since,omitempty"`
+		Ratio   float64   `url:"ratio,omitempty"`
+		Skipped string    `url:"-"`
+		Status  string
+		hidden  string
+	}{
+		pagination: pagination{Page: 2},
+		Search:     "go http",
+		Tags:       []string{"a", "b"},
+		Active:     &active,
+		Since:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skipped:    "skip",
+		Status:     "open",
+		hidden:     "hidden",
+	}
+
+	values, err := EncodeQuery(&query)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "Status=open&active=true&page=2&q=go+http&since=2024-01-02T03%3A04%3A05Z&tag=a&tag=b"
+	if values.Encode() != expected {
+		t.Errorf("expected %s, got %s", expected, values.Encode())
+	}
+}
+
+// TestEncodeQuery_Values verifies that url.Values and string maps are accepted as-is.
+func TestEncodeQuery_Values(t *testing.T) {
+	values, err := EncodeQuery(url.Values{"a": {"1", "2"}})
+	if err != nil || values.Encode() != "a=1&a=2" {
+		t.Errorf("expected a=1&a=2, got %s (%v)", values.Encode(), err)
+	}
+
+	values, err = EncodeQuery(map[string]string{"b": "3"})
+	if err != nil || values.Encode() != "b=3" {
+		t.Errorf("expected b=3, got %s (%v)", values.Encode(), err)
+	}
+}
+
+// TestEncodeQuery_Unsupported verifies that values which cannot be encoded are rejected.
+func TestEncodeQuery_Unsupported(t *testing.T) {
+	if _, err := EncodeQuery(42); err == nil {
+		t.Error("expected error for non-struct query, got nil")
+	}
+
+	query := struct {
+		Filter map[string]string `url:"filter"`
+	}{Filter: map[string]string{"a": "b"}}
+	if _, err := EncodeQuery(query); err == nil {
+		t.Error("expected error for unsupported field type, got nil")
+	}
+}
@@ -0,0 +1,98 @@
+package webs
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// defaultMaxRedirects is the number of redirects followed by default, matching http.Client.
+const defaultMaxRedirects = 10
+
+// Redirect describes one hop of the redirect chain followed to obtain a Response.
+type Redirect struct {
+	// URL is the URL that answered with a redirect.
+	URL string
+	// StatusCode is the redirect status code returned by URL.
+	StatusCode int
+	// Location is the URL the redirect was followed to.
+	Location string
+}
+
+// DisableRedirects configures the client to return redirect responses as-is instead of following them.
+func (cb *ClientBuilder) DisableRedirects() *ClientBuilder {
+	cb.disableRedirects = true
+	return cb
+}
+
+// SetMaxRedirects sets the maximum number of redirects followed for a request. Following more fails with
+// ErrTooManyRedirects. Defaults to 10.
+func (cb *ClientBuilder) SetMaxRedirects(maxRedirects int) *ClientBuilder {
+	cb.maxRedirects = maxRedirects
+	cb.limitRedirects = true
+	return cb
+}
+
+// SetSensitiveHeaders sets headers, such as a custom API key header, that are only forwarded on redirects to the host
+// and port of the original request. Authorization, Proxy-Authorization, and Cookie are always treated as sensitive once this
+// option is set, including for redirects to subdomains, which the standard library would otherwise allow.
+func (cb *ClientBuilder) SetSensitiveHeaders(headers ...string) *ClientBuilder {
+	if cb.sensitiveHeaders == nil {
+		cb.sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+	}
+	for _, header := range headers {
+		cb.sensitiveHeaders = append(cb.sensitiveHeaders, http.CanonicalHeaderKey(header))
+	}
+	return cb
+}
+
+// getCheckRedirect returns the redirect policy of the http.Client, or nil to keep the standard library default.
+func (cb *ClientBuilder) getCheckRedirect() func(*http.Request, []*http.Request) error {
+	if cb.disableRedirects {
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	if !cb.limitRedirects && cb.sensitiveHeaders == nil {
+		return nil
+	}
+
+	maxRedirects := defaultMaxRedirects
+	if cb.limitRedirects {
+		maxRedirects = cb.maxRedirects
+	}
+	sensitive := cb.sensitiveHeaders
+	return func(request *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, maxRedirects)
+		}
+		if request.URL.Host != via[0].URL.Host {
+			for _, header := range sensitive {
+				request.Header.Del(header)
+			}
+		}
+		return nil
+	}
+}
+
+// Redirects returns the redirects followed to obtain the response, in order. It is empty when the response was
+// returned by the first URL requested.
+func (r *Response) Redirects() []Redirect {
+	return r.redirects
+}
+
+// redirectChain walks back from the final response through the requests that were issued for each redirect.
+func redirectChain(response *http.Response) []Redirect {
+	var redirects []Redirect
+	for request := response.Request; request != nil && request.Response != nil; request = request.Response.Request {
+		previous := request.Response
+		redirects = append(redirects, Redirect{
+			URL:        previous.Request.URL.String(),
+			StatusCode: previous.StatusCode,
+			Location:   request.URL.String(),
+		})
+	}
+	for i, j := 0, len(redirects)-1; i < j; i, j = i+1, j-1 {
+		redirects[i], redirects[j] = redirects[j], redirects[i]
+	}
+	return redirects
+}
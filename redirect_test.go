@@ -0,0 +1,152 @@
+package webs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newRedirectServer starts a server that redirects /hop/N to /hop/N-1 until /hop/0, which echoes the X-Api-Key and
+// Authorization headers it received. /away redirects to the given target.
+func newRedirectServer(t *testing.T, away string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hop/3", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/hop/2", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/hop/2", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/hop/1", http.StatusFound)
+	})
+	mux.HandleFunc("/hop/1", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/hop/0", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/hop/0", echoAuthHeaders)
+	mux.HandleFunc("/away", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, away, http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// echoAuthHeaders writes the X-Api-Key and Authorization headers of the request to the response.
+func echoAuthHeaders(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(r.Header.Get("X-Api-Key") + "|" + r.Header.Get("Authorization")))
+}
+
+// TestResponse_Redirects verifies that the redirect chain followed for a request is recorded on the Response.
+func TestResponse_Redirects(t *testing.T) {
+	server := newRedirectServer(t, "")
+
+	res, err := NewClientBuilder().Build().Get(server.URL+"/hop/3", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []Redirect{
+		{URL: server.URL + "/hop/3", StatusCode: http.StatusMovedPermanently, Location: server.URL + "/hop/2"},
+		{URL: server.URL + "/hop/2", StatusCode: http.StatusFound, Location: server.URL + "/hop/1"},
+		{URL: server.URL + "/hop/1", StatusCode: http.StatusTemporaryRedirect, Location: server.URL + "/hop/0"},
+	}
+	redirects := res.Redirects()
+	if len(redirects) != len(expected) {
+		t.Fatalf("expected %d redirects, got %v", len(expected), redirects)
+	}
+	for i := range expected {
+		if redirects[i] != expected[i] {
+			t.Errorf("expected redirect %d to be %v, got %v", i, expected[i], redirects[i])
+		}
+	}
+
+	res, _ = NewClientBuilder().Build().Get(server.URL+"/hop/0", nil)
+	if len(res.Redirects()) != 0 {
+		t.Errorf("expected no redirects, got %v", res.Redirects())
+	}
+}
+
+// TestClientBuilder_DisableRedirects verifies that redirect responses are returned instead of being followed.
+func TestClientBuilder_DisableRedirects(t *testing.T) {
+	server := newRedirectServer(t, "")
+
+	res, err := NewClientBuilder().DisableRedirects().Build().Get(server.URL+"/hop/3", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusMovedPermanently {
+		t.Errorf("expected status 301, got %d", res.StatusCode())
+	}
+	if location := res.Headers().Get("Location"); location != "/hop/2" {
+		t.Errorf("expected Location /hop/2, got %s", location)
+	}
+}
+
+// TestClientBuilder_SetMaxRedirects verifies that requests fail with ErrTooManyRedirects past the configured limit.
+func TestClientBuilder_SetMaxRedirects(t *testing.T) {
+	server := newRedirectServer(t, "")
+
+	t.Run("withinLimit", func(t *testing.T) {
+		res, err := NewClientBuilder().SetMaxRedirects(3).Build().Get(server.URL+"/hop/3", nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.StatusCode() != http.StatusOK {
+			t.Errorf("expected status 200, got %d", res.StatusCode())
+		}
+	})
+
+	t.Run("overLimit", func(t *testing.T) {
+		_, err := NewClientBuilder().SetMaxRedirects(2).Build().Get(server.URL+"/hop/3", nil)
+		if !errors.Is(err, ErrTooManyRedirects) {
+			t.Errorf("expected ErrTooManyRedirects, got %v", err)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		_, err := NewClientBuilder().SetMaxRedirects(0).Build().Get(server.URL+"/hop/1", nil)
+		if !errors.Is(err, ErrTooManyRedirects) {
+			t.Errorf("expected ErrTooManyRedirects, got %v", err)
+		}
+	})
+}
+
+// TestClientBuilder_SetSensitiveHeaders verifies that sensitive headers are kept on same-host redirects only.
+func TestClientBuilder_SetSensitiveHeaders(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(echoAuthHeaders))
+	defer other.Close()
+	server := newRedirectServer(t, other.URL)
+
+	headers := http.Header{"X-Api-Key": {"secret"}, "Authorization": {"Bearer token"}}
+	client := NewClientBuilder().SetSensitiveHeaders("x-api-key").Build()
+
+	t.Run("sameHost", func(t *testing.T) {
+		res, err := client.Get(server.URL+"/hop/2", headers)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "secret|Bearer token" {
+			t.Errorf("expected headers to be kept, got %s", res.String())
+		}
+	})
+
+	t.Run("otherHost", func(t *testing.T) {
+		res, err := client.Get(server.URL+"/away", headers)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "|" {
+			t.Errorf("expected headers to be removed, got %s", res.String())
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		res, err := NewClientBuilder().Build().Get(server.URL+"/away", headers)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		// The standard library compares host names without ports, so both headers are forwarded to the other server.
+		if res.String() != "secret|Bearer token" {
+			t.Errorf("expected headers to be forwarded by default, got %s", res.String())
+		}
+	})
+}
@@ -1,21 +1,30 @@
 package webs
 
 import (
+	"context"
 	"net/http"
 )
 
 // RequestHandler represents an interface for handling HTTP requests and responses.
 // ExecuteRequest sends an HTTP request with the specified method, URL, headers, and body, returning a Response and an error.
+// ExecuteRequestContext does the same while binding the request to the given context.
 type RequestHandler interface {
 	ExecuteRequest(method, url string, headers http.Header, body interface{}) (*Response, error)
+	ExecuteRequestContext(ctx context.Context, method, url string, headers http.Header, body interface{}) (*Response, error)
 }
 
-// Requester is an interface for making HTTP requests including Do, Get, Post, Put, Patch, and Delete methods.
+// Requester is an interface for making HTTP requests including Do, Get, Post, Put, Patch, and Delete methods,
+// along with their context-aware counterparts.
 type Requester interface {
 	Do(req *http.Request) (*Response, error)
 	Get(url string, headers http.Header) (*Response, error)
+	GetContext(ctx context.Context, url string, headers http.Header) (*Response, error)
 	Post(url string, headers http.Header, body interface{}) (*Response, error)
+	PostContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error)
 	Put(url string, headers http.Header, body interface{}) (*Response, error)
+	PutContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error)
 	Patch(url string, headers http.Header, body interface{}) (*Response, error)
+	PatchContext(ctx context.Context, url string, headers http.Header, body interface{}) (*Response, error)
 	Delete(url string, headers http.Header) (*Response, error)
+	DeleteContext(ctx context.Context, url string, headers http.Header) (*Response, error)
 }
@@ -0,0 +1,197 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"github.com/madalinpopa/webs/internal/utils"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RequestBuilder configures a single request fluently before sending it through the Client.
+// It is created with Client.R and must not be reused once a request has been sent.
+type RequestBuilder struct {
+	client      *Client
+	ctx         context.Context
+	headers     http.Header
+	query       url.Values
+	pathParams  map[string]string
+	body        interface{}
+	options     []func(context.Context) context.Context
+	result      interface{}
+	errorResult interface{}
+	errs        []error
+}
+
+// R creates a RequestBuilder for a single request sent with the client.
+func (c *Client) R() *RequestBuilder {
+	return &RequestBuilder{
+		client:  c,
+		ctx:     context.Background(),
+		headers: make(http.Header),
+		query:   make(url.Values),
+	}
+}
+
+// Context binds the request to ctx.
+func (rb *RequestBuilder) Context(ctx context.Context) *RequestBuilder {
+	rb.ctx = ctx
+	return rb
+}
+
+// Header sets a header of the request, replacing any value set previously for the same key, including the client's
+// default headers.
+func (rb *RequestBuilder) Header(key, value string) *RequestBuilder {
+	rb.headers.Set(key, value)
+	return rb
+}
+
+// Headers adds all the given headers to the request. Each key replaces the client's default headers for that key.
+func (rb *RequestBuilder) Headers(headers http.Header) *RequestBuilder {
+	for key, values := range headers {
+		for _, value := range values {
+			rb.headers.Add(key, value)
+		}
+	}
+	return rb
+}
+
+// Query adds a query parameter to the request URL.
+func (rb *RequestBuilder) Query(key, value string) *RequestBuilder {
+	rb.query.Add(key, value)
+	return rb
+}
+
+// QueryParams adds the parameters encoded from query, either url.Values or a struct with `url` tags, to the request URL.
+func (rb *RequestBuilder) QueryParams(query interface{}) *RequestBuilder {
+	values, err := EncodeQuery(query)
+	if err != nil {
+		rb.errs = append(rb.errs, err)
+		return rb
+	}
+	for key, items := range values {
+		rb.query[key] = append(rb.query[key], items...)
+	}
+	return rb
+}
+
+// PathParam sets the value substituted for the {name} placeholder of the request path.
+func (rb *RequestBuilder) PathParam(name, value string) *RequestBuilder {
+	if rb.pathParams == nil {
+		rb.pathParams = make(map[string]string)
+	}
+	rb.pathParams[name] = value
+	return rb
+}
+
+// Body sets the request body, encoded the same way as the body given to ExecuteRequest.
+func (rb *RequestBuilder) Body(body interface{}) *RequestBuilder {
+	rb.body = body
+	return rb
+}
+
+// Timeout overrides the client's total request timeout for the request, including reading the response body.
+func (rb *RequestBuilder) Timeout(timeout time.Duration) *RequestBuilder {
+	rb.options = append(rb.options, func(ctx context.Context) context.Context {
+		return WithRequestTimeout(ctx, timeout)
+	})
+	return rb
+}
+
+// MaxResponseBodySize overrides the client's maximum response body size for the request.
+func (rb *RequestBuilder) MaxResponseBodySize(limit int64) *RequestBuilder {
+	rb.options = append(rb.options, func(ctx context.Context) context.Context {
+		return WithMaxResponseBodySize(ctx, limit)
+	})
+	return rb
+}
+
+// Result sets the target that a successful (2xx) response body is decoded into.
+func (rb *RequestBuilder) Result(target interface{}) *RequestBuilder {
+	rb.result = target
+	return rb
+}
+
+// ErrorResult sets the target that a non-2xx response body is decoded into.
+func (rb *RequestBuilder) ErrorResult(target interface{}) *RequestBuilder {
+	rb.errorResult = target
+	return rb
+}
+
+// Get sends the request with the GET method to the given path.
+func (rb *RequestBuilder) Get(path string) (*Response, error) {
+	return rb.Execute(http.MethodGet, path)
+}
+
+// Post sends the request with the POST method to the given path.
+func (rb *RequestBuilder) Post(path string) (*Response, error) {
+	return rb.Execute(http.MethodPost, path)
+}
+
+// Put sends the request with the PUT method to the given path.
+func (rb *RequestBuilder) Put(path string) (*Response, error) {
+	return rb.Execute(http.MethodPut, path)
+}
+
+// Patch sends the request with the PATCH method to the given path.
+func (rb *RequestBuilder) Patch(path string) (*Response, error) {
+	return rb.Execute(http.MethodPatch, path)
+}
+
+// Delete sends the request with the DELETE method to the given path.
+func (rb *RequestBuilder) Delete(path string) (*Response, error) {
+	return rb.Execute(http.MethodDelete, path)
+}
+
+// Execute sends the request with the given method to path, which may be relative to the client's base URL and may
+// contain {name} placeholders. The response body is decoded into the Result or ErrorResult target depending on its
+// status code.
+func (rb *RequestBuilder) Execute(method, path string) (*Response, error) {
+	if err := errors.Join(rb.errs...); err != nil {
+		return nil, err
+	}
+
+	target, err := rb.buildURL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := rb.ctx
+	for _, option := range rb.options {
+		ctx = option(ctx)
+	}
+
+	response, err := rb.client.execute(ctx, method, target, utils.ReplaceHeaders(rb.client.headers, rb.headers), rb.body)
+	if response == nil {
+		return nil, err
+	}
+	if decodeErr := rb.decode(response); decodeErr != nil && err == nil {
+		err = decodeErr
+	}
+	return response, err
+}
+
+// buildURL expands the path placeholders and appends the query parameters.
+func (rb *RequestBuilder) buildURL(path string) (string, error) {
+	if rb.pathParams != nil {
+		expanded, err := ExpandPath(path, rb.pathParams)
+		if err != nil {
+			return "", err
+		}
+		path = expanded
+	}
+	return AppendQuery(path, rb.query)
+}
+
+// decode decodes the response body into the target matching its status code. Empty bodies are left undecoded.
+func (rb *RequestBuilder) decode(response *Response) error {
+	target := rb.errorResult
+	if isSuccess(response.StatusCode()) {
+		target = rb.result
+	}
+	if target == nil || len(response.Bytes()) == 0 {
+		return nil
+	}
+	return response.Decode(target)
+}
@@ -0,0 +1,166 @@
+package webs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRequestBuilder_Post verifies that headers, path parameters, query parameters, and body are all applied and that
+// a successful response is decoded into the Result target.
+func TestRequestBuilder_Post(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"uri":     r.URL.RequestURI(),
+			"default": r.Header.Get("X-Default"),
+			"trace":   r.Header.Get("X-Trace"),
+			"name":    payload["name"],
+		})
+	}))
+	defer server.Close()
+
+	headers := http.Header{}
+	headers.Set("X-Default", "default")
+	client := NewClientBuilder().SetBaseURL(server.URL).SetHeaders(headers).Build()
+
+	var result map[string]string
+	res, err := client.R().
+		Header("X-Trace", "trace-1").
+		PathParam("id", "a b").
+		Query("page", "2").
+		QueryParams(struct {
+			Sort string `url:"sort"`
+		}{Sort: "name"}).
+		Body(map[string]string{"name": "order"}).
+		Result(&result).
+		Post("/users/{id}/orders")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusCreated {
+		t.Errorf("expected status code 201, got %d", res.StatusCode())
+	}
+
+	expected := map[string]string{
+		"uri":     "/users/a%20b/orders?page=2&sort=name",
+		"default": "default",
+		"trace":   "trace-1",
+		"name":    "order",
+	}
+	for key, value := range expected {
+		if result[key] != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, result[key])
+		}
+	}
+}
+
+// TestRequestBuilder_ErrorResult verifies that a non-2xx response body is decoded into the ErrorResult target only.
+func TestRequestBuilder_ErrorResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"title":"invalid"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().EnableHTTPErrors(true).Build()
+
+	var result, apiErr map[string]string
+	res, err := client.R().Result(&result).ErrorResult(&apiErr).Get(server.URL)
+	if !errors.Is(err, ErrClientError) {
+		t.Errorf("expected ErrClientError, got %v", err)
+	}
+	if res == nil {
+		t.Fatal("expected response, got nil")
+	}
+	if apiErr["title"] != "invalid" {
+		t.Errorf("expected error title invalid, got %v", apiErr)
+	}
+	if result != nil {
+		t.Errorf("expected result to stay empty, got %v", result)
+	}
+}
+
+// TestRequestBuilder_Timeout verifies that the per-request timeout aborts a slow request.
+func TestRequestBuilder_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClientBuilder().Build()
+	_, err := client.R().Context(context.Background()).Timeout(20 * time.Millisecond).Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// TestRequestBuilder_InvalidQuery verifies that an invalid query is reported without sending the request.
+func TestRequestBuilder_InvalidQuery(t *testing.T) {
+	client := NewClientBuilder().Build()
+	if _, err := client.R().QueryParams(42).Get("https://unreachable.invalid"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+// TestRequestBuilder_MaxResponseBodySizeBeforeContext verifies that the body size limit is kept when the context is
+// set after it.
+func TestRequestBuilder_MaxResponseBodySizeBeforeContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().Build()
+	_, err := client.R().MaxResponseBodySize(4).Context(context.Background()).Get(server.URL)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+// TestRequestBuilder_HeaderOverridesDefault verifies that a request header replaces the client default for the same
+// key, so the body is encoded with the codec of the overriding Content-Type.
+func TestRequestBuilder_HeaderOverridesDefault(t *testing.T) {
+	type item struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	var contentTypes []string
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = r.Header.Values("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().
+		SetHeaders(http.Header{"Content-Type": {"application/json"}}).
+		Build()
+
+	_, err := client.R().
+		Header("Content-Type", "application/xml").
+		Body(item{Name: "widget"}).
+		Post(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(contentTypes) != 1 || contentTypes[0] != "application/xml" {
+		t.Errorf("expected a single Content-Type application/xml, got %v", contentTypes)
+	}
+	if body != "<item><name>widget</name></item>" {
+		t.Errorf("expected XML body, got %q", body)
+	}
+}
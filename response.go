@@ -2,6 +2,8 @@ package webs
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -11,6 +13,25 @@ type Response struct {
 	statusCode int
 	headers    http.Header
 	body       []byte
+	stream     io.ReadCloser
+	attempts   int
+	redirects  []Redirect
+	request    *http.Request
+	codecs     *CodecRegistry
+}
+
+// NewResponse creates a Response with the given status code, headers, and body.
+// It is intended for middleware that short-circuits a request with a synthetic response.
+func NewResponse(statusCode int, headers http.Header, body []byte) *Response {
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	return &Response{
+		status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		statusCode: statusCode,
+		headers:    headers,
+		body:       body,
+	}
 }
 
 // Status returns the HTTP status string of the response.
@@ -23,6 +44,11 @@ func (r *Response) StatusCode() int {
 	return r.statusCode
 }
 
+// Attempts returns the number of attempts made to obtain the response, including retries.
+func (r *Response) Attempts() int {
+	return r.attempts
+}
+
 // Headers returns the HTTP headers of the response.
 func (r *Response) Headers() http.Header {
 	return r.headers
@@ -42,3 +68,13 @@ func (r *Response) String() string {
 func (r *Response) UnmarshalJson(target interface{}) error {
 	return json.Unmarshal(r.body, target)
 }
+
+// Decode parses the body of the response into the target interface using the codec matching its Content-Type.
+// A response without Content-Type is decoded as JSON.
+func (r *Response) Decode(target interface{}) error {
+	codecs := r.codecs
+	if codecs == nil {
+		codecs = defaultCodecRegistry()
+	}
+	return codecs.unmarshal(r.headers.Get("Content-Type"), r.body, target)
+}
@@ -63,3 +63,36 @@ func TestResponse_UnmarshalJson(t *testing.T) {
 		t.Errorf("UnmarshalJson() target = %v, want %v", target, map[string]string{"key": "value"})
 	}
 }
+
+// TestResponse_Decode tests that Decode selects the codec from the Content-Type header of the response.
+func TestResponse_Decode(t *testing.T) {
+	t.Run("problemJson", func(t *testing.T) {
+		resp := &Response{
+			headers: http.Header{"Content-Type": []string{"application/problem+json; charset=utf-8"}},
+			body:    []byte(`{"title":"Not Found"}`),
+		}
+		var target map[string]string
+		if err := resp.Decode(&target); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if target["title"] != "Not Found" {
+			t.Errorf("Decode() target = %v, want title Not Found", target)
+		}
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		resp := &Response{
+			headers: http.Header{"Content-Type": []string{"application/xml"}},
+			body:    []byte(`<item><name>foo</name></item>`),
+		}
+		var target struct {
+			Name string `xml:"name"`
+		}
+		if err := resp.Decode(&target); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if target.Name != "foo" {
+			t.Errorf("Decode() target = %v, want name foo", target)
+		}
+	})
+}
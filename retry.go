@@ -0,0 +1,215 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultRetryBaseDelay is the default delay before the first retry when the policy does not specify one.
+	defaultRetryBaseDelay = 100 * time.Millisecond
+
+	// defaultRetryMaxDelay is the default upper bound for the delay between two attempts.
+	defaultRetryMaxDelay = 5 * time.Second
+
+	// maxDrainBytes caps how much of a discarded response body is read so the connection can be reused.
+	maxDrainBytes = 4 << 10
+)
+
+// Jitter selects how randomness is applied to the exponential backoff between retry attempts.
+type Jitter int
+
+const (
+	// FullJitter picks a random delay between zero and the exponential backoff for the attempt.
+	FullJitter Jitter = iota
+
+	// DecorrelatedJitter picks a random delay between the base delay and three times the previous delay.
+	DecorrelatedJitter
+
+	// NoJitter uses the plain exponential backoff without any randomness.
+	NoJitter
+)
+
+// RetryableFunc reports whether an attempt that ended with the given status code or error should be retried.
+// The status code is zero when the attempt failed before a response was received.
+type RetryableFunc func(statusCode int, err error) bool
+
+// RetryPolicy describes how the Client retries failed requests.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the backoff used for the first retry. Defaults to 100ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts. Defaults to 5s.
+	MaxDelay time.Duration
+
+	// Jitter selects the randomisation strategy applied to the backoff.
+	Jitter Jitter
+
+	// Retryable decides whether an attempt should be retried. Defaults to DefaultRetryable.
+	Retryable RetryableFunc
+
+	// RetryNonIdempotent allows retrying methods such as POST and PATCH, which are skipped by default.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryable retries transport errors, 429 Too Many Requests and every 5xx status except 501 Not Implemented.
+// Errors caused by a cancelled or expired context are never retried.
+func DefaultRetryable(statusCode int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// isIdempotent reports whether the HTTP method is idempotent as defined by RFC 9110.
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// getMaxAttempts returns the number of attempts allowed for the request, taking the method's idempotency into account.
+func (p RetryPolicy) getMaxAttempts(request *http.Request) int {
+	if p.MaxAttempts < 2 {
+		return 1
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(request.Method) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// getBaseDelay returns the configured base delay or the default value if not set.
+func (p RetryPolicy) getBaseDelay() time.Duration {
+	if p.BaseDelay > 0 {
+		return p.BaseDelay
+	}
+	return defaultRetryBaseDelay
+}
+
+// getMaxDelay returns the configured maximum delay or the default value if not set.
+func (p RetryPolicy) getMaxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return defaultRetryMaxDelay
+}
+
+// isRetryable applies the configured predicate, or DefaultRetryable, to the outcome of an attempt.
+func (p RetryPolicy) isRetryable(response *http.Response, err error) bool {
+	statusCode := 0
+	if response != nil {
+		statusCode = response.StatusCode
+	}
+	if p.Retryable != nil {
+		return p.Retryable(statusCode, err)
+	}
+	return DefaultRetryable(statusCode, err)
+}
+
+// backoff computes the delay to wait after the given attempt, based on the previous delay for decorrelated jitter.
+func (p RetryPolicy) backoff(attempt int, previous time.Duration) time.Duration {
+	base, maxDelay := p.getBaseDelay(), p.getMaxDelay()
+
+	switch p.Jitter {
+	case DecorrelatedJitter:
+		if previous < base {
+			previous = base
+		}
+		upper := min(previous*3, maxDelay)
+		if upper <= base {
+			return upper
+		}
+		return base + rand.N(upper-base)
+	default:
+		delay := maxDelay
+		if shift := attempt - 1; shift < 32 && base<<shift > 0 {
+			delay = min(base<<shift, maxDelay)
+		}
+		if p.Jitter == NoJitter {
+			return delay
+		}
+		return rand.N(delay + 1)
+	}
+}
+
+// execute sends the request with send, retrying according to the policy.
+// It returns the final response, the number of attempts made, and the error of the last attempt.
+func (p RetryPolicy) execute(send func(*http.Request) (*http.Response, error), request *http.Request) (*http.Response, int, error) {
+	ctx := request.Context()
+	maxAttempts := p.getMaxAttempts(request)
+
+	var delay time.Duration
+	current := request
+	for attempt := 1; ; attempt++ {
+		response, err := send(current)
+		if attempt >= maxAttempts || ctx.Err() != nil || !p.isRetryable(response, err) {
+			return response, attempt, err
+		}
+
+		next, ok := rewind(request)
+		if !ok {
+			return response, attempt, err
+		}
+		if response != nil {
+			drain(response.Body)
+		}
+
+		delay = p.backoff(attempt, delay)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+		current = next
+	}
+}
+
+// rewind returns a copy of the request with a fresh body so it can be sent again.
+// It reports false when the body cannot be replayed.
+func rewind(request *http.Request) (*http.Request, bool) {
+	next := request.Clone(request.Context())
+	if request.Body == nil || request.Body == http.NoBody {
+		return next, true
+	}
+	if request.GetBody == nil {
+		return nil, false
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next.Body = body
+	return next, true
+}
+
+// drain reads a bounded amount of the body and closes it so the underlying connection can be reused.
+func drain(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	_ = body.Close()
+}
+
+// sleep waits for the given duration or until the context is done, whichever happens first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package webs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer returns a test server that answers with failStatus for the first failures requests and 200 afterwards.
+func flakyServer(t *testing.T, failures int32, failStatus int, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(failStatus)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestRetryPolicy_RetriesIdempotentRequests verifies that a GET request is retried until it succeeds and that the attempts are reported.
+func TestRetryPolicy_RetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(t, 2, http.StatusServiceUnavailable, &calls)
+
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}).
+		Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", res.StatusCode())
+	}
+	if res.Attempts() != 3 {
+		t.Errorf("expected 3 attempts, got %d", res.Attempts())
+	}
+}
+
+// TestRetryPolicy_StopsAfterMaxAttempts verifies that the last failed response is returned once the attempts are exhausted.
+func TestRetryPolicy_StopsAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(t, 5, http.StatusBadGateway, &calls)
+
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != http.StatusBadGateway {
+		t.Errorf("expected status code 502, got %d", res.StatusCode())
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got %d", calls.Load())
+	}
+}
+
+// TestRetryPolicy_SkipsNonIdempotentRequests verifies that POST requests are not retried by default.
+func TestRetryPolicy_SkipsNonIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(t, 1, http.StatusServiceUnavailable, &calls)
+
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}).
+		Build()
+
+	res, err := client.Post(server.URL, nil, map[string]string{"foo": "bar"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Attempts() != 1 {
+		t.Errorf("expected 1 attempt, got %d", res.Attempts())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 call, got %d", calls.Load())
+	}
+}
+
+// TestRetryPolicy_ResendsBody verifies that every retry of a non-idempotent request carries the full marshalled body.
+func TestRetryPolicy_ResendsBody(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(t, 1, http.StatusServiceUnavailable, &calls)
+
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true}).
+		Build()
+
+	res, err := client.Post(server.URL, nil, map[string]string{"foo": "bar"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", res.Attempts())
+	}
+	if res.String() != `{"foo":"bar"}` {
+		t.Errorf("expected echoed body, got %s", res.String())
+	}
+}
+
+// TestRetryPolicy_CustomPredicate verifies that a custom Retryable predicate decides which outcomes are retried.
+func TestRetryPolicy_CustomPredicate(t *testing.T) {
+	var calls atomic.Int32
+	server := flakyServer(t, 1, http.StatusConflict, &calls)
+
+	client := NewClientBuilder().
+		SetRetryPolicy(RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			Retryable: func(statusCode int, err error) bool {
+				return statusCode == http.StatusConflict
+			},
+		}).
+		Build()
+
+	res, err := client.Get(server.URL, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got %d", res.Attempts())
+	}
+}
+
+// TestDefaultRetryable checks the default classification of status codes and errors.
+func TestDefaultRetryable(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{"ok", http.StatusOK, nil, false},
+		{"notFound", http.StatusNotFound, nil, false},
+		{"tooManyRequests", http.StatusTooManyRequests, nil, true},
+		{"internalServerError", http.StatusInternalServerError, nil, true},
+		{"notImplemented", http.StatusNotImplemented, nil, false},
+		{"transportError", 0, errors.New("connection reset"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryable(tt.statusCode, tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRetryPolicy_Backoff verifies that every jitter strategy stays within its documented bounds.
+func TestRetryPolicy_Backoff(t *testing.T) {
+	base, maxDelay := 10*time.Millisecond, 100*time.Millisecond
+
+	t.Run("noJitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: base, MaxDelay: maxDelay, Jitter: NoJitter}
+		for attempt, want := range []time.Duration{10, 20, 40, 80, 100, 100} {
+			if got := policy.backoff(attempt+1, 0); got != want*time.Millisecond {
+				t.Errorf("attempt %d: expected %v, got %v", attempt+1, want*time.Millisecond, got)
+			}
+		}
+	})
+
+	t.Run("fullJitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: base, MaxDelay: maxDelay, Jitter: FullJitter}
+		for attempt := 1; attempt <= 50; attempt++ {
+			if got := policy.backoff(attempt, 0); got < 0 || got > maxDelay {
+				t.Errorf("attempt %d: expected delay within [0, %v], got %v", attempt, maxDelay, got)
+			}
+		}
+	})
+
+	t.Run("decorrelatedJitter", func(t *testing.T) {
+		policy := RetryPolicy{BaseDelay: base, MaxDelay: maxDelay, Jitter: DecorrelatedJitter}
+		var delay time.Duration
+		for attempt := 1; attempt <= 50; attempt++ {
+			delay = policy.backoff(attempt, delay)
+			if delay < base || delay > maxDelay {
+				t.Errorf("attempt %d: expected delay within [%v, %v], got %v", attempt, base, maxDelay, delay)
+			}
+		}
+	})
+}
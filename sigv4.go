@@ -0,0 +1,316 @@
+package webs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// sigV4Algorithm identifies AWS Signature Version 4 with HMAC-SHA256.
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+	// sigV4TimeFormat is the format of the X-Amz-Date header.
+	sigV4TimeFormat = "20060102T150405Z"
+
+	// sigV4DateFormat is the format of the date in the credential scope.
+	sigV4DateFormat = "20060102"
+
+	// UnsignedPayload is the payload hash sent when the body is excluded from the signature.
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// maxPresignExpiry is the longest validity accepted for a presigned URL.
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// ErrUnsignableBody is returned when the body of a request cannot be read for signing without consuming it.
+var ErrUnsignableBody = errors.New("request body cannot be replayed for signing")
+
+// AWSCredentials are the credentials used to sign requests with AWS Signature Version 4.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is sent as X-Amz-Security-Token for temporary credentials.
+	SessionToken string
+}
+
+// CredentialsProvider returns the credentials used to sign a request bound to ctx. It is called for every request,
+// so implementations can rotate temporary credentials.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (AWSCredentials, error)
+}
+
+// CredentialsProviderFunc adapts a function to the CredentialsProvider interface.
+type CredentialsProviderFunc func(ctx context.Context) (AWSCredentials, error)
+
+// Credentials calls f with ctx.
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (AWSCredentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a CredentialsProvider that always returns the given credentials.
+func StaticCredentials(accessKeyID, secretAccessKey, sessionToken string) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (AWSCredentials, error) {
+		return AWSCredentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}, nil
+	})
+}
+
+// SigV4Signer is an Authenticator that signs requests with AWS Signature Version 4. The signature covers the method,
+// URL, body, and every header of the final request except the ones commonly changed in transit.
+type SigV4Signer struct {
+	// Credentials provides the credentials used to sign requests.
+	Credentials CredentialsProvider
+
+	// Region and Service make up the credential scope, e.g. "us-east-1" and "s3".
+	Region  string
+	Service string
+
+	// UnsignedPayload excludes the body from the signature, which S3 accepts for streamed uploads whose body cannot be
+	// read twice.
+	UnsignedPayload bool
+
+	now func() time.Time
+}
+
+// NewSigV4Signer creates a SigV4Signer for the given region and service.
+func NewSigV4Signer(credentials CredentialsProvider, region, service string) *SigV4Signer {
+	return &SigV4Signer{Credentials: credentials, Region: region, Service: service}
+}
+
+// Authenticate signs the request. It is equivalent to Sign. Use ChainAuthenticators to also send credentials such as
+// an API key header, placing the signer last so that the header is signed.
+func (s *SigV4Signer) Authenticate(request *http.Request) error {
+	return s.Sign(request)
+}
+
+// Sign adds the X-Amz-Date and Authorization headers to the request, as well as X-Amz-Security-Token for temporary
+// credentials and X-Amz-Content-Sha256 for S3. The body is hashed through GetBody so it can still be sent.
+func (s *SigV4Signer) Sign(request *http.Request) error {
+	credentials, err := s.Credentials.Credentials(request.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+	payloadHash, err := s.payloadHash(request)
+	if err != nil {
+		return err
+	}
+
+	signingTime := s.getNow().UTC()
+	request.Header.Del("Authorization")
+	request.Header.Set("X-Amz-Date", signingTime.Format(sigV4TimeFormat))
+	if credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+	if s.Service == "s3" || payloadHash == UnsignedPayload {
+		request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := sigV4Headers(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		s.canonicalURI(request.URL),
+		sigV4Query(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := s.scope(signingTime)
+	signature := s.signature(credentials, signingTime, scope, canonicalRequest)
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// Presign returns the URL of the request with the signature in its query string, valid for expires. The headers of
+// the request are signed and must be sent by whoever uses the URL. The payload is always unsigned.
+func (s *SigV4Signer) Presign(request *http.Request, expires time.Duration) (string, error) {
+	if expires < time.Second || expires > maxPresignExpiry {
+		return "", fmt.Errorf("invalid presign expiry %s: must be between 1s and %s", expires, maxPresignExpiry)
+	}
+	credentials, err := s.Credentials.Credentials(request.Context())
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+
+	signingTime := s.getNow().UTC()
+	scope := s.scope(signingTime)
+	signedHeaders, canonicalHeaders := sigV4Headers(request)
+
+	presigned := *request.URL
+	query := presigned.Query()
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", credentials.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", signingTime.Format(sigV4TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+	if credentials.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	canonicalQuery := sigV4Query(query)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		s.canonicalURI(request.URL),
+		canonicalQuery,
+		canonicalHeaders,
+		signedHeaders,
+		UnsignedPayload,
+	}, "\n")
+
+	signature := s.signature(credentials, signingTime, scope, canonicalRequest)
+	presigned.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return presigned.String(), nil
+}
+
+// payloadHash returns the hex-encoded SHA-256 hash of the request body, or UnsignedPayload when it is not signed.
+func (s *SigV4Signer) payloadHash(request *http.Request) (string, error) {
+	if s.UnsignedPayload {
+		return UnsignedPayload, nil
+	}
+	hash := sha256.New()
+	if err := hashRequestBody(request, hash); err != nil {
+		return "", fmt.Errorf("%w, set UnsignedPayload to sign it without its body", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// canonicalURI returns the URI-encoded path. Services other than S3 expect the path to be encoded twice.
+func (s *SigV4Signer) canonicalURI(u *url.URL) string {
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	path = sigV4Escape(path, false)
+	if s.Service != "s3" {
+		path = sigV4Escape(path, false)
+	}
+	return path
+}
+
+// scope returns the credential scope of a signature made at signingTime.
+func (s *SigV4Signer) scope(signingTime time.Time) string {
+	return strings.Join([]string{signingTime.Format(sigV4DateFormat), s.Region, s.Service, "aws4_request"}, "/")
+}
+
+// signature derives the signing key and returns the hex-encoded signature of the canonical request.
+func (s *SigV4Signer) signature(credentials AWSCredentials, signingTime time.Time, scope, canonicalRequest string) string {
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		signingTime.Format(sigV4TimeFormat),
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), signingTime.Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// getNow returns the current time, which tests can replace.
+func (s *SigV4Signer) getNow() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// sigV4IgnoredHeaders are headers left out of the signature because proxies and the transport may change them.
+var sigV4IgnoredHeaders = map[string]bool{
+	"Authorization":     true,
+	"User-Agent":        true,
+	"X-Amzn-Trace-Id":   true,
+	"Expect":            true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
+}
+
+// sigV4Headers returns the signed headers list and the canonical headers block, which includes the Host header.
+func sigV4Headers(request *http.Request) (string, string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for key, items := range request.Header {
+		if sigV4IgnoredHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		trimmed := make([]string, len(items))
+		for i, item := range items {
+			trimmed[i] = strings.Join(strings.Fields(item), " ")
+		}
+		values[strings.ToLower(key)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// sigV4Query returns the canonical query string, sorted by key and value, with keys and values URI-encoded.
+func sigV4Query(query url.Values) string {
+	type pair struct{ key, value string }
+	pairs := make([]pair, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, pair{sigV4Escape(key, true), sigV4Escape(value, true)})
+		}
+	}
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].key != pairs[b].key {
+			return pairs[a].key < pairs[b].key
+		}
+		return pairs[a].value < pairs[b].value
+	})
+
+	encoded := make([]string, len(pairs))
+	for i, p := range pairs {
+		encoded[i] = p.key + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+// sigV4Escape URI-encodes every byte except unreserved characters, and slashes unless encodeSlash is set.
+func sigV4Escape(value string, encodeSlash bool) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			escaped.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,96 @@
+package webs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// StreamResponse represents an HTTP response whose body is read directly from the connection instead of being buffered.
+// The caller is responsible for closing it once the body has been consumed.
+type StreamResponse struct {
+	status     string
+	statusCode int
+	headers    http.Header
+	body       io.ReadCloser
+	decoder    *json.Decoder
+	attempts   int
+}
+
+// newStreamResponse converts a Response produced in streaming mode into a StreamResponse.
+// Responses without a live stream, such as synthetic ones returned by middleware, expose their buffered body instead.
+func newStreamResponse(response *Response) *StreamResponse {
+	body := response.stream
+	if body == nil {
+		body = io.NopCloser(bytes.NewReader(response.body))
+	}
+	return &StreamResponse{
+		status:     response.status,
+		statusCode: response.statusCode,
+		headers:    response.headers,
+		body:       body,
+		attempts:   response.attempts,
+	}
+}
+
+// Status returns the HTTP status string of the response.
+func (r *StreamResponse) Status() string {
+	return r.status
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (r *StreamResponse) StatusCode() int {
+	return r.statusCode
+}
+
+// Attempts returns the number of attempts made to obtain the response, including retries.
+func (r *StreamResponse) Attempts() int {
+	return r.attempts
+}
+
+// Headers returns the HTTP headers of the response.
+func (r *StreamResponse) Headers() http.Header {
+	return r.headers
+}
+
+// Body returns the unread response body. Reading from it consumes the stream.
+func (r *StreamResponse) Body() io.ReadCloser {
+	return r.body
+}
+
+// JsonDecoder returns the json.Decoder reading from the body, allowing large documents to be processed incrementally.
+// The same decoder is returned by every call and shared with DecodeJson, so no buffered input is lost between calls.
+func (r *StreamResponse) JsonDecoder() *json.Decoder {
+	if r.decoder == nil {
+		r.decoder = json.NewDecoder(r.body)
+	}
+	return r.decoder
+}
+
+// DecodeJson decodes the next JSON value from the body into the target interface.
+func (r *StreamResponse) DecodeJson(target interface{}) error {
+	return r.JsonDecoder().Decode(target)
+}
+
+// Close closes the response body, releasing the underlying connection.
+func (r *StreamResponse) Close() error {
+	return r.body.Close()
+}
+
+// Stream sends an HTTP request with the specified method, URL, headers, and body, and returns a StreamResponse whose
+// body is read directly from the connection. The caller must close the returned StreamResponse.
+func (c *Client) Stream(method, url string, headers http.Header, body interface{}) (*StreamResponse, error) {
+	return c.StreamContext(context.Background(), method, url, headers, body)
+}
+
+// StreamContext sends an HTTP request bound to ctx and returns a StreamResponse whose body is read directly from the
+// connection. Cancelling ctx aborts reading the body. The caller must close the returned StreamResponse.
+func (c *Client) StreamContext(ctx context.Context, method, url string, headers http.Header, body interface{}) (*StreamResponse, error) {
+	response, err := c.ExecuteRequestContext(withStreaming(ctx), method, url, headers, body)
+	if response == nil {
+		return nil, err
+	}
+	return newStreamResponse(response), err
+}
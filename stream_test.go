@@ -0,0 +1,153 @@
+package webs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClient_Stream verifies that the response body is exposed as a stream and can be decoded incrementally.
+func TestClient_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, "[")
+		for i := 0; i < 100; i++ {
+			if i > 0 {
+				_, _ = fmt.Fprint(w, ",")
+			}
+			_, _ = fmt.Fprintf(w, `{"id":%d}`, i)
+		}
+		_, _ = fmt.Fprint(w, "]")
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().Build()
+	res, err := client.Stream(http.MethodGet, server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer func() {
+		_ = res.Close()
+	}()
+
+	if res.StatusCode() != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", res.StatusCode())
+	}
+	if res.Headers().Get("Content-Type") != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %s", res.Headers().Get("Content-Type"))
+	}
+
+	decoder := res.JsonDecoder()
+	if _, err := decoder.Token(); err != nil {
+		t.Fatalf("expected opening token, got %v", err)
+	}
+	count := 0
+	for decoder.More() {
+		var item struct {
+			Id int `json:"id"`
+		}
+		if err := decoder.Decode(&item); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if item.Id != count {
+			t.Errorf("expected id %d, got %d", count, item.Id)
+		}
+		count++
+	}
+	if count != 100 {
+		t.Errorf("expected 100 items, got %d", count)
+	}
+}
+
+// TestClient_StreamDecodeJsonSequence verifies that consecutive DecodeJson calls read consecutive values of a stream.
+func TestClient_StreamDecodeJsonSequence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n")
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().Build()
+	res, err := client.Stream(http.MethodGet, server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer func() {
+		_ = res.Close()
+	}()
+
+	for want := 1; want <= 3; want++ {
+		var item struct {
+			A int `json:"a"`
+		}
+		decode := res.DecodeJson
+		if want == 2 {
+			decode = res.JsonDecoder().Decode
+		}
+		if err := decode(&item); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if item.A != want {
+			t.Errorf("expected %d, got %d", want, item.A)
+		}
+	}
+	if err := res.DecodeJson(&struct{}{}); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+// TestClient_StreamCanceled verifies that cancelling the context aborts reading a streamed body.
+func TestClient_StreamCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first chunk"))
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClientBuilder().Build()
+	res, err := client.StreamContext(ctx, http.MethodGet, server.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer func() {
+		_ = res.Close()
+	}()
+
+	cancel()
+	if _, err := io.ReadAll(res.Body()); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestClient_StreamHTTPError verifies that a failed streamed request returns an *HTTPError with a readable body snippet.
+func TestClient_StreamHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "upstream"})
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().EnableHTTPErrors(true).Build()
+	res, err := client.Stream(http.MethodGet, server.URL, nil, nil)
+	if !errors.Is(err, ErrServerError) {
+		t.Fatalf("expected ErrServerError, got %v", err)
+	}
+	if res == nil {
+		t.Fatal("expected response, got nil")
+	}
+	var content map[string]string
+	if err := res.DecodeJson(&content); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content["error"] != "upstream" {
+		t.Errorf("expected error upstream, got %s", content["error"])
+	}
+}
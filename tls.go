@@ -0,0 +1,164 @@
+package webs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// tlsSettings holds the TLS options configured on the ClientBuilder.
+type tlsSettings struct {
+	rootCAs            [][]byte
+	certificates       []tls.Certificate
+	minVersion         uint16
+	cipherSuites       []uint16
+	serverName         string
+	insecureSkipVerify bool
+	pins               map[string][]string
+	reloader           *certificateReloader
+}
+
+// configured reports whether any TLS option was set.
+func (s *tlsSettings) configured() bool {
+	return len(s.rootCAs) > 0 || len(s.certificates) > 0 || s.minVersion != 0 || len(s.cipherSuites) > 0 ||
+		s.serverName != "" || s.insecureSkipVerify || len(s.pins) > 0 || s.reloader != nil
+}
+
+// AddRootCAs trusts the PEM-encoded CA certificates in addition to the system roots.
+func (cb *ClientBuilder) AddRootCAs(pemCerts []byte) *ClientBuilder {
+	if !x509.NewCertPool().AppendCertsFromPEM(pemCerts) {
+		cb.errs = append(cb.errs, errors.New("invalid root CAs: no PEM certificate found"))
+		return cb
+	}
+	cb.tls.rootCAs = append(cb.tls.rootCAs, pemCerts)
+	return cb
+}
+
+// AddRootCAsFromFile trusts the PEM-encoded CA certificates stored in the file at path in addition to the system roots.
+func (cb *ClientBuilder) AddRootCAsFromFile(path string) *ClientBuilder {
+	pemCerts, err := os.ReadFile(path)
+	if err != nil {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid root CAs: %w", err))
+		return cb
+	}
+	return cb.AddRootCAs(pemCerts)
+}
+
+// AddClientCertificate adds a PEM-encoded certificate and private key presented to servers requesting mutual TLS.
+func (cb *ClientBuilder) AddClientCertificate(certPEM, keyPEM []byte) *ClientBuilder {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid client certificate: %w", err))
+		return cb
+	}
+	cb.tls.certificates = append(cb.tls.certificates, certificate)
+	return cb
+}
+
+// AddClientCertificateFromFiles adds a certificate and private key, read from PEM files, presented to servers
+// requesting mutual TLS.
+func (cb *ClientBuilder) AddClientCertificateFromFiles(certFile, keyFile string) *ClientBuilder {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		cb.errs = append(cb.errs, fmt.Errorf("invalid client certificate: %w", err))
+		return cb
+	}
+	cb.tls.certificates = append(cb.tls.certificates, certificate)
+	return cb
+}
+
+// SetMinTLSVersion sets the minimum TLS version accepted, such as tls.VersionTLS12 or tls.VersionTLS13.
+func (cb *ClientBuilder) SetMinTLSVersion(version uint16) *ClientBuilder {
+	cb.tls.minVersion = version
+	return cb
+}
+
+// SetCipherSuites restricts the cipher suites offered for TLS 1.2 and earlier. TLS 1.3 suites are not configurable.
+func (cb *ClientBuilder) SetCipherSuites(suites ...uint16) *ClientBuilder {
+	cb.tls.cipherSuites = suites
+	return cb
+}
+
+// SetServerName overrides the server name sent with SNI and used to verify the server certificate.
+func (cb *ClientBuilder) SetServerName(serverName string) *ClientBuilder {
+	cb.tls.serverName = serverName
+	return cb
+}
+
+// InsecureSkipVerify disables the verification of server certificates. It must only be used for local development;
+// building a client with it enabled logs a warning.
+func (cb *ClientBuilder) InsecureSkipVerify(skip bool) *ClientBuilder {
+	cb.tls.insecureSkipVerify = skip
+	return cb
+}
+
+// applyTLSSettings layers the builder's TLS options on a clone of the TLS configuration of the transport.
+func (cb *ClientBuilder) applyTLSSettings(transport *http.Transport) {
+	if !cb.tls.configured() {
+		return
+	}
+
+	config := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+
+	if len(cb.tls.rootCAs) > 0 {
+		pool := rootPool(config.RootCAs)
+		for _, pemCerts := range cb.tls.rootCAs {
+			pool.AppendCertsFromPEM(pemCerts)
+		}
+		config.RootCAs = pool
+	}
+	if len(cb.tls.certificates) > 0 {
+		config.Certificates = append(config.Certificates, cb.tls.certificates...)
+	}
+	if cb.tls.reloader != nil {
+		config.GetClientCertificate = cb.tls.reloader.withLogger(cb.getLogger()).GetClientCertificate
+	}
+	if len(cb.tls.pins) > 0 {
+		config.VerifyConnection = chainVerifiers(config.VerifyConnection, verifyPins(cb.tls.pins, cb.tls.serverName))
+	}
+	if cb.tls.minVersion != 0 {
+		config.MinVersion = cb.tls.minVersion
+	}
+	if len(cb.tls.cipherSuites) > 0 {
+		config.CipherSuites = cb.tls.cipherSuites
+	}
+	if cb.tls.serverName != "" {
+		config.ServerName = cb.tls.serverName
+	}
+	if cb.tls.insecureSkipVerify {
+		cb.getLogger().Warn("webs: TLS certificate verification is disabled, do not use InsecureSkipVerify outside local development")
+		config.InsecureSkipVerify = true
+	}
+
+	transport.TLSClientConfig = config
+}
+
+// rootPool returns a copy of the given pool, or of the system pool when it is nil, that can be extended safely.
+func rootPool(pool *x509.CertPool) *x509.CertPool {
+	if pool != nil {
+		return pool.Clone()
+	}
+	if system, err := x509.SystemCertPool(); err == nil {
+		return system
+	}
+	return x509.NewCertPool()
+}
+
+// chainVerifiers combines an existing VerifyConnection callback, which may be nil, with another one.
+func chainVerifiers(first, second func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	if first == nil {
+		return second
+	}
+	return func(state tls.ConnectionState) error {
+		if err := first(state); err != nil {
+			return err
+		}
+		return second(state)
+	}
+}
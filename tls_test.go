@@ -0,0 +1,230 @@
+package webs
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA is a certificate authority issuing certificates for the TLS tests.
+type testCA struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+// newTestCA creates a self-signed certificate authority.
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "webs test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	certificate, _ := x509.ParseCertificate(der)
+	return &testCA{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue creates a certificate signed by the CA for the given common name and usage, returned as PEM.
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// newMutualTLSServer starts a TLS server for "api.internal" requiring client certificates issued by ca.
+// The handler answers with the common name of the client certificate.
+func newMutualTLSServer(t *testing.T, ca *testCA) *httptest.Server {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, "api.internal", x509.ExtKeyUsageServerAuth)
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.certificate)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestClientBuilder_MutualTLS verifies that a private CA and a client certificate allow calling a mutual TLS server.
+func TestClientBuilder_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	server := newMutualTLSServer(t, ca)
+	certPEM, keyPEM := ca.issue(t, "client-1", x509.ExtKeyUsageClientAuth)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	for path, content := range map[string][]byte{caFile: ca.pem, certFile: certPEM, keyFile: keyPEM} {
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	t.Run("fromFiles", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAsFromFile(caFile).
+			AddClientCertificateFromFiles(certFile, keyFile).
+			SetServerName("api.internal").
+			SetMinTLSVersion(tls.VersionTLS12).
+			Build()
+
+		res, err := client.Get(server.URL, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.String() != "client-1" {
+			t.Errorf("expected client-1, got %s", res.String())
+		}
+	})
+
+	t.Run("fromBytes", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			AddClientCertificate(certPEM, keyPEM).
+			SetServerName("api.internal").
+			Build()
+
+		if _, err := client.Get(server.URL, nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("withoutClientCertificate", func(t *testing.T) {
+		client := NewClientBuilder().AddRootCAs(ca.pem).SetServerName("api.internal").Build()
+		if _, err := client.Get(server.URL, nil); err == nil {
+			t.Error("expected handshake error, got nil")
+		}
+	})
+
+	t.Run("wrongServerName", func(t *testing.T) {
+		client := NewClientBuilder().
+			AddRootCAs(ca.pem).
+			AddClientCertificate(certPEM, keyPEM).
+			SetServerName("other.internal").
+			Build()
+		if _, err := client.Get(server.URL, nil); err == nil {
+			t.Error("expected verification error, got nil")
+		}
+	})
+}
+
+// TestClientBuilder_MinTLSVersion verifies that servers below the minimum TLS version are rejected.
+func TestClientBuilder_MinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	rootCAs := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client := NewClientBuilder().AddRootCAs(rootCAs).SetMinTLSVersion(tls.VersionTLS13).Build()
+	if _, err := client.Get(server.URL, nil); err == nil {
+		t.Error("expected protocol version error, got nil")
+	}
+
+	client = NewClientBuilder().
+		AddRootCAs(rootCAs).
+		SetCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256).
+		Build()
+	if _, err := client.Get(server.URL, nil); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// TestClientBuilder_InsecureSkipVerify verifies that verification can be disabled and that a warning is logged.
+func TestClientBuilder_InsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClientBuilder().
+		SetLogger(slog.New(slog.NewTextHandler(&logs, nil))).
+		InsecureSkipVerify(true).
+		Build()
+	if _, err := client.Get(server.URL, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") {
+		t.Errorf("expected a warning to be logged, got %q", logs.String())
+	}
+}
+
+// TestClientBuilder_InvalidTLSSettings verifies that invalid certificates are reported by every request.
+func TestClientBuilder_InvalidTLSSettings(t *testing.T) {
+	tests := map[string]*ClientBuilder{
+		"rootCAs":     NewClientBuilder().AddRootCAs([]byte("not a certificate")),
+		"rootCAsFile": NewClientBuilder().AddRootCAsFromFile(filepath.Join(t.TempDir(), "missing.pem")),
+		"clientCert":  NewClientBuilder().AddClientCertificate([]byte("cert"), []byte("key")),
+	}
+	for name, builder := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := builder.Build().Get("https://server.example", nil); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package webs
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// getRoundTripper returns the RoundTripper used by the built client. It starts from the transport given to
+// SetTransport, or from a fresh transport holding the builder defaults, runs the ConfigureTransport hooks, and finally
+// applies the settings that were set explicitly on the builder.
+func (cb *ClientBuilder) getRoundTripper() http.RoundTripper {
+	var transport *http.Transport
+	switch base := cb.transport.(type) {
+	case nil:
+		transport = cb.getTransport()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return base
+	}
+
+	for _, configure := range cb.transportHooks {
+		configure(transport)
+	}
+	cb.applyTransportSettings(transport, cb.transport != nil)
+	return transport
+}
+
+// applyTransportSettings applies the timeout and pool settings that were set explicitly on the builder, or clears the
+// timeouts when they are disabled. The DialContext of a custom transport is kept and wrapped to enforce the dial
+// timeout, while hooks replacing the DialContext of the builder's own transport are responsible for its timeout.
+func (cb *ClientBuilder) applyTransportSettings(transport *http.Transport, custom bool) {
+	if cb.disableTimeouts || cb.responseTimeout > 0 {
+		transport.ResponseHeaderTimeout = cb.getResponseTimeout()
+	}
+	if cb.disableTimeouts || cb.tlsHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = cb.getTLSHandshakeTimeout()
+	}
+	if cb.disableTimeouts || cb.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = cb.getIdleConnTimeout()
+	}
+	if cb.disableTimeouts || cb.expectContinue > 0 {
+		transport.ExpectContinueTimeout = cb.getExpectContinueTimeout()
+	}
+
+	if cb.proxyConfigured() {
+		transport.Proxy = cb.getProxy(transport.Proxy)
+	}
+
+	cb.applyTLSSettings(transport)
+
+	if cb.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cb.maxIdleConnsPerHost
+	}
+	if cb.maxIdleConns > 0 {
+		transport.MaxIdleConns = cb.maxIdleConns
+	}
+	if cb.maxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = cb.maxConnsPerHost
+	}
+
+	switch {
+	case transport.DialContext == nil:
+		transport.DialContext = (&net.Dialer{
+			Timeout:   cb.getConnectionTimeout(),
+			KeepAlive: cb.keepAlive,
+		}).DialContext
+	case custom && cb.getConnectionTimeout() > 0:
+		transport.DialContext = dialWithTimeout(transport.DialContext, cb.getConnectionTimeout())
+	}
+}
+
+// dialWithTimeout wraps dial so that establishing a connection fails once timeout has elapsed.
+func dialWithTimeout(dial func(ctx context.Context, network, address string) (net.Conn, error), timeout time.Duration) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return dial(ctx, network, address)
+	}
+}
@@ -0,0 +1,128 @@
+package webs
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRoundTripper counts the requests it forwards to the default transport.
+type countingRoundTripper struct {
+	calls atomic.Int32
+}
+
+func (rt *countingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	rt.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+// TestClientBuilder_SetTransportRoundTripper verifies that a custom RoundTripper is used as-is to send requests.
+func TestClientBuilder_SetTransportRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	roundTripper := &countingRoundTripper{}
+	client := NewClientBuilder().SetTransport(roundTripper).SetMaxIdleConnectionsPerHost(10).Build()
+
+	if client.client.Transport != roundTripper {
+		t.Fatalf("expected custom round tripper, got %T", client.client.Transport)
+	}
+	if _, err := client.Get(server.URL, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if roundTripper.calls.Load() != 1 {
+		t.Errorf("expected 1 call through the round tripper, got %d", roundTripper.calls.Load())
+	}
+}
+
+// TestClientBuilder_SetTransportLayersSettings verifies that a custom *http.Transport is cloned, keeps its own settings,
+// and receives the settings set explicitly on the builder.
+func TestClientBuilder_SetTransportLayersSettings(t *testing.T) {
+	base := &http.Transport{
+		DisableCompression:    true,
+		MaxConnsPerHost:       7,
+		ResponseHeaderTimeout: time.Minute,
+		IdleConnTimeout:       time.Minute,
+	}
+
+	client := NewClientBuilder().
+		SetTransport(base).
+		SetResponseHeaderTimeout(3 * time.Second).
+		SetMaxIdleConnectionsPerHost(4).
+		Build()
+
+	transport, ok := client.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.client.Transport)
+	}
+	if transport == base {
+		t.Error("expected the transport to be cloned")
+	}
+	if !transport.DisableCompression || transport.MaxConnsPerHost != 7 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("expected custom transport settings to be kept, got %+v", transport)
+	}
+	if transport.ResponseHeaderTimeout != 3*time.Second {
+		t.Errorf("expected response header timeout to be 3s, got %s", transport.ResponseHeaderTimeout)
+	}
+	if transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("expected max idle connections per host to be 4, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if base.ResponseHeaderTimeout != time.Minute {
+		t.Error("expected the original transport to be left untouched")
+	}
+}
+
+// TestClientBuilder_ConfigureTransport verifies that hooks customise the transport and that explicit builder settings win.
+func TestClientBuilder_ConfigureTransport(t *testing.T) {
+	client := NewClientBuilder().
+		SetMaxConnectionsPerHost(5).
+		ConfigureTransport(func(transport *http.Transport) {
+			transport.ForceAttemptHTTP2 = false
+			transport.DisableCompression = true
+			transport.MaxConnsPerHost = 50
+			transport.MaxIdleConns = 20
+		}).
+		Build()
+
+	transport := client.client.Transport.(*http.Transport)
+	if transport.ForceAttemptHTTP2 || !transport.DisableCompression {
+		t.Errorf("expected hook settings to be applied, got %+v", transport)
+	}
+	if transport.MaxIdleConns != 20 {
+		t.Errorf("expected max idle connections to be 20, got %d", transport.MaxIdleConns)
+	}
+	if transport.MaxConnsPerHost != 5 {
+		t.Errorf("expected explicit max connections per host to win, got %d", transport.MaxConnsPerHost)
+	}
+}
+
+// TestClientBuilder_SetTransportDialTimeout verifies that the dial timeout is enforced around a custom DialContext.
+func TestClientBuilder_SetTransportDialTimeout(t *testing.T) {
+	base := &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	client := NewClientBuilder().SetTransport(base).SetDialTimeout(20 * time.Millisecond).Build()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Get("http://example.invalid", nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected dial error, got nil")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the dial timeout to abort the request")
+	}
+}
@@ -0,0 +1,57 @@
+package webs
+
+import (
+	"context"
+	"net/http"
+)
+
+// ExecuteAs sends a request with the given handler and decodes the response body into a value of type T using the
+// codec matching its Content-Type. An empty body leaves the zero value. Decoding failures are returned as *DecodeError.
+// A non-2xx response is not decoded and is returned with an *HTTPError describing the request that was sent, so error
+// payloads are never mistaken for T.
+func ExecuteAs[T any](ctx context.Context, handler RequestHandler, method, url string, headers http.Header, body interface{}) (T, *Response, error) {
+	var result T
+
+	response, err := handler.ExecuteRequestContext(ctx, method, url, headers, body)
+	if err != nil {
+		return result, response, err
+	}
+	if !isSuccess(response.StatusCode()) {
+		if response.request == nil {
+			return result, response, newStatusError(method, url, response)
+		}
+		return result, response, newHTTPError(response.request, response)
+	}
+	if len(response.Bytes()) == 0 {
+		return result, response, nil
+	}
+	if err := response.Decode(&result); err != nil {
+		return result, response, newDecodeError(response, err)
+	}
+	return result, response, nil
+}
+
+// GetAs sends a GET request and decodes the response body into a value of type T.
+func GetAs[T any](ctx context.Context, handler RequestHandler, url string) (T, *Response, error) {
+	return ExecuteAs[T](ctx, handler, http.MethodGet, url, nil, nil)
+}
+
+// PostAs sends a POST request with the given body and decodes the response body into a value of type T.
+func PostAs[T any](ctx context.Context, handler RequestHandler, url string, body interface{}) (T, *Response, error) {
+	return ExecuteAs[T](ctx, handler, http.MethodPost, url, nil, body)
+}
+
+// PutAs sends a PUT request with the given body and decodes the response body into a value of type T.
+func PutAs[T any](ctx context.Context, handler RequestHandler, url string, body interface{}) (T, *Response, error) {
+	return ExecuteAs[T](ctx, handler, http.MethodPut, url, nil, body)
+}
+
+// PatchAs sends a PATCH request with the given body and decodes the response body into a value of type T.
+func PatchAs[T any](ctx context.Context, handler RequestHandler, url string, body interface{}) (T, *Response, error) {
+	return ExecuteAs[T](ctx, handler, http.MethodPatch, url, nil, body)
+}
+
+// DeleteAs sends a DELETE request and decodes the response body into a value of type T.
+func DeleteAs[T any](ctx context.Context, handler RequestHandler, url string) (T, *Response, error) {
+	return ExecuteAs[T](ctx, handler, http.MethodDelete, url, nil, nil)
+}
@@ -0,0 +1,162 @@
+package webs
+
+import (
+	"context"
+	"errors"
+	"github.com/h2non/gock"
+	"net/http"
+	"testing"
+)
+
+// user is the typed payload exchanged in the generic helper tests.
+type user struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestGetAs verifies that GetAs decodes the response body into the requested type.
+func TestGetAs(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/users/1").
+		Reply(200).
+		JSON(user{Id: 1, Name: "John"})
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	result, res, err := GetAs[user](context.Background(), client, "https://server.com/users/1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.StatusCode() != 200 {
+		t.Errorf("expected status code 200, got %d", res.StatusCode())
+	}
+	if result.Id != 1 || result.Name != "John" {
+		t.Errorf("expected user 1 John, got %+v", result)
+	}
+}
+
+// TestPostAs verifies that PostAs encodes the body and decodes the response into the requested type.
+func TestPostAs(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Post("/users").
+		BodyString(`{"name":"Jane"}`).
+		Reply(201).
+		JSON(user{Id: 2, Name: "Jane"})
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	result, _, err := PostAs[*user](context.Background(), client, "https://server.com/users", map[string]string{"name": "Jane"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result == nil || result.Id != 2 {
+		t.Errorf("expected user 2, got %+v", result)
+	}
+}
+
+// TestGetAs_DecodeError verifies that decoding failures are wrapped with the status code and a body snippet.
+func TestGetAs_DecodeError(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Get("/users/1").
+		Reply(200).
+		SetHeader("Content-Type", "application/json").
+		BodyString("<html>maintenance</html>")
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	_, res, err := GetAs[user](context.Background(), client, "https://server.com/users/1")
+	if res == nil {
+		t.Fatal("expected response, got nil")
+	}
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected *DecodeError, got %v", err)
+	}
+	if decodeErr.StatusCode != http.StatusOK {
+		t.Errorf("expected status code 200, got %d", decodeErr.StatusCode)
+	}
+	if decodeErr.Snippet != "<html>maintenance</html>" {
+		t.Errorf("expected body snippet, got %q", decodeErr.Snippet)
+	}
+}
+
+// TestDeleteAs_EmptyBody verifies that an empty body leaves the zero value without error.
+func TestDeleteAs_EmptyBody(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://server.com").
+		Delete("/users/1").
+		Reply(204)
+
+	client := NewClientBuilder().Build()
+	gock.InterceptClient(client.client)
+
+	result, _, err := DeleteAs[user](context.Background(), client, "https://server.com/users/1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != (user{}) {
+		t.Errorf("expected zero value, got %+v", result)
+	}
+}
+
+// TestGetAs_ErrorStatus verifies that a non-2xx response is returned with an *HTTPError instead of being decoded.
+func TestGetAs_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseURL    string
+		url        string
+		statusCode int
+		category   error
+	}{
+		{"clientError", "", "https://server.com/users/1", http.StatusNotFound, ErrClientError},
+		{"serverError", "", "https://server.com/users/1", http.StatusInternalServerError, ErrServerError},
+		{"baseURL", "https://server.com", "users/1", http.StatusNotFound, ErrClientError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://server.com").
+				Get("/users/1").
+				Reply(tt.statusCode).
+				JSON(user{Id: 0, Name: "internal error"})
+
+			builder := NewClientBuilder()
+			if tt.baseURL != "" {
+				builder.SetBaseURL(tt.baseURL)
+			}
+			client := builder.Build()
+			gock.InterceptClient(client.client)
+
+			result, res, err := GetAs[user](context.Background(), client, tt.url)
+			if !errors.Is(err, tt.category) {
+				t.Fatalf("expected %v, got %v", tt.category, err)
+			}
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.statusCode || httpErr.Method != http.MethodGet {
+				t.Fatalf("expected *HTTPError for GET with status %d, got %v", tt.statusCode, err)
+			}
+			if httpErr.URL != "https://server.com/users/1" {
+				t.Errorf("expected URL https://server.com/users/1, got %s", httpErr.URL)
+			}
+			if result != (user{}) {
+				t.Errorf("expected zero value, got %+v", result)
+			}
+			if res == nil || res.StatusCode() != tt.statusCode {
+				t.Errorf("expected response with status %d, got %v", tt.statusCode, res)
+			}
+		})
+	}
+}
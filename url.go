@@ -0,0 +1,116 @@
+package webs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingPathParam is returned when a path template references a parameter that has no value.
+var ErrMissingPathParam = errors.New("missing path parameter")
+
+// ErrDotSegment is returned when a path parameter or a path relative to the base URL contains a "." or ".." segment,
+// which would let it escape its position in the path.
+var ErrDotSegment = errors.New("path contains a dot-segment")
+
+// ExpandPath replaces every {name} placeholder in template with the matching value from params, escaped so it is
+// always a single path segment. For example "/users/{id}/orders" with id "a/b" becomes "/users/a%2Fb/orders".
+// The values "." and ".." are rejected with ErrDotSegment.
+func ExpandPath(template string, params map[string]string) (string, error) {
+	var builder strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			builder.WriteString(template)
+			return builder.String(), nil
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in path template %q", template)
+		}
+		end += start
+
+		name := template[start+1 : end]
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMissingPathParam, name)
+		}
+		if isDotSegment(value) {
+			return "", fmt.Errorf("%w: parameter %s is %q", ErrDotSegment, name, value)
+		}
+		builder.WriteString(template[:start])
+		builder.WriteString(url.PathEscape(value))
+		template = template[end+1:]
+	}
+}
+
+// AppendQuery adds the parameters encoded from query to the query string of rawURL.
+// The query can be url.Values or a struct with `url` tags, as accepted by EncodeQuery.
+func AppendQuery(rawURL string, query interface{}) (string, error) {
+	values, err := EncodeQuery(query)
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return rawURL, nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	merged := parsed.Query()
+	for key, items := range values {
+		merged[key] = append(merged[key], items...)
+	}
+	parsed.RawQuery = merged.Encode()
+	return parsed.String(), nil
+}
+
+// resolveURL resolves rawURL against base. Absolute URLs are returned unchanged, while relative ones are appended to
+// the base path, keeping the query parameters of both. Relative paths containing dot-segments are rejected with
+// ErrDotSegment so they cannot leave the base path.
+func resolveURL(base *url.URL, rawURL string) (string, error) {
+	if base == nil {
+		return rawURL, nil
+	}
+	reference, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if reference.IsAbs() || reference.Host != "" {
+		return rawURL, nil
+	}
+
+	for _, segment := range strings.Split(reference.Path, "/") {
+		if isDotSegment(segment) {
+			return "", fmt.Errorf("%w: %q", ErrDotSegment, rawURL)
+		}
+	}
+
+	resolved := *base
+	escapedPath := strings.TrimSuffix(base.EscapedPath(), "/")
+	if reference.Path != "" {
+		escapedPath += "/" + strings.TrimPrefix(reference.EscapedPath(), "/")
+	}
+	resolved.Path, err = url.PathUnescape(escapedPath)
+	if err != nil {
+		return "", err
+	}
+	resolved.RawPath = escapedPath
+
+	switch {
+	case base.RawQuery == "":
+		resolved.RawQuery = reference.RawQuery
+	case reference.RawQuery != "":
+		resolved.RawQuery = base.RawQuery + "&" + reference.RawQuery
+	}
+	resolved.Fragment = reference.Fragment
+	return resolved.String(), nil
+}
+
+// isDotSegment reports whether the path segment is "." or "..".
+func isDotSegment(segment string) bool {
+	return segment == "." || segment == ".."
+}
@@ -0,0 +1,137 @@
+package webs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestExpandPath verifies that path templates are expanded with escaped values and report missing parameters.
+func TestExpandPath(t *testing.T) {
+	t.Run("escapesValues", func(t *testing.T) {
+		path, err := ExpandPath("/users/{id}/orders/{order}", map[string]string{"id": "a/b c", "order": "42"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if path != "/users/a%2Fb%20c/orders/42" {
+			t.Errorf("expected /users/a%%2Fb%%20c/orders/42, got %s", path)
+		}
+	})
+
+	t.Run("missingParam", func(t *testing.T) {
+		_, err := ExpandPath("/users/{id}", nil)
+		if !errors.Is(err, ErrMissingPathParam) {
+			t.Errorf("expected ErrMissingPathParam, got %v", err)
+		}
+	})
+
+	t.Run("unterminatedPlaceholder", func(t *testing.T) {
+		if _, err := ExpandPath("/users/{id", map[string]string{"id": "1"}); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("dotSegments", func(t *testing.T) {
+		for _, value := range []string{".", ".."} {
+			if _, err := ExpandPath("/users/{id}/orders", map[string]string{"id": value}); !errors.Is(err, ErrDotSegment) {
+				t.Errorf("expected ErrDotSegment for %q, got %v", value, err)
+			}
+		}
+		path, err := ExpandPath("/files/{name}", map[string]string{"name": "..."})
+		if err != nil || path != "/files/..." {
+			t.Errorf("expected /files/..., got %s (%v)", path, err)
+		}
+	})
+}
+
+// TestResolveURL verifies how relative and absolute URLs are resolved against a base URL.
+func TestResolveURL(t *testing.T) {
+	base, _ := url.Parse("https://api.example.com/v1/?key=1")
+
+	tests := []struct {
+		name     string
+		rawURL   string
+		expected string
+	}{
+		{"relative", "users", "https://api.example.com/v1/users?key=1"},
+		{"rooted", "/users/a%2Fb", "https://api.example.com/v1/users/a%2Fb?key=1"},
+		{"query", "/users?page=2", "https://api.example.com/v1/users?key=1&page=2"},
+		{"empty", "", "https://api.example.com/v1?key=1"},
+		{"absolute", "https://other.example.com/x", "https://other.example.com/x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := resolveURL(base, tt.rawURL)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, resolved)
+			}
+		})
+	}
+
+	t.Run("dotSegments", func(t *testing.T) {
+		for _, rawURL := range []string{"../admin", "/users/../../admin", "./users", "%2E%2E/admin", "users/.."} {
+			if _, err := resolveURL(base, rawURL); !errors.Is(err, ErrDotSegment) {
+				t.Errorf("expected ErrDotSegment for %s, got %v", rawURL, err)
+			}
+		}
+	})
+
+	t.Run("noBase", func(t *testing.T) {
+		resolved, err := resolveURL(nil, "users")
+		if err != nil || resolved != "users" {
+			t.Errorf("expected users unchanged, got %s (%v)", resolved, err)
+		}
+	})
+}
+
+// TestAppendQuery verifies that encoded parameters are added to the existing query string.
+func TestAppendQuery(t *testing.T) {
+	query := struct {
+		Page int `url:"page"`
+	}{Page: 2}
+
+	resolved, err := AppendQuery("https://server.com/items?sort=name", query)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolved != "https://server.com/items?page=2&sort=name" {
+		t.Errorf("expected https://server.com/items?page=2&sort=name, got %s", resolved)
+	}
+}
+
+// TestClientBuilder_SetBaseURL verifies that verb helpers resolve relative paths against the base URL.
+func TestClientBuilder_SetBaseURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().SetBaseURL(server.URL + "/api/v1").Build()
+
+	path, err := ExpandPath("/users/{id}", map[string]string{"id": "a/b"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res, err := client.Get(path, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.String() != "/api/v1/users/a%2Fb" {
+		t.Errorf("expected /api/v1/users/a%%2Fb, got %s", res.String())
+	}
+}
+
+// TestClientBuilder_InvalidBaseURL verifies that an invalid base URL is reported by every request.
+func TestClientBuilder_InvalidBaseURL(t *testing.T) {
+	client := NewClientBuilder().SetBaseURL("/relative").Build()
+
+	if _, err := client.Get("users", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}